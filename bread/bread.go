@@ -2,6 +2,7 @@ package bread
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -96,7 +97,13 @@ func Cookie(n, v string) {
 
 // GetWithClient returns the HTML returned by the url using a provided HTTP client
 func GetWithClient(url string, client *http.Client) (string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	return GetWithContext(context.Background(), url, client)
+}
+
+// GetWithContext returns the HTML returned by the url using a provided HTTP client,
+// aborting the request when the context is cancelled
+func GetWithContext(ctx context.Context, url string, client *http.Client) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		if debug {
 			panic("Couldn't create GET request to " + url)
@@ -1,6 +1,7 @@
 package bread
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -26,6 +27,21 @@ func TestGetWithClient(t *testing.T) {
 	}
 }
 
+func TestGetWithContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := GetWithContext(ctx, server.URL, http.DefaultClient)
+	if err == nil {
+		t.Fatalf("expected an error for a cancelled context, got nil")
+	}
+}
+
 func TestPostWithClient(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, _ := io.ReadAll(r.Body)
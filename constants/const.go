@@ -8,22 +8,25 @@ const QCRI_ENV_VAR string = "QCRI_API_KEY"
 const YANDEX_ENV_VAR string = "YANDEX_API_KEY"
 
 var BASE_URLS = map[string]string{
-	"GOOGLE_TRANSLATE":    "https://translate.google.com/m",
-	"PONS":                "https://en.pons.com/translate/",
-	"YANDEX":              "https://translate.yandex.net/api/{version}/tr.json/{endpoint}",
-	"LINGUEE":             "https://www.linguee.com/",
-	"QCRI":                "https://mt.qcri.org/api/v1/{endpoint}?",
-	"DEEPL":               "https://api.deepl.com/v2/",
-	"DEEPL_FREE":          "https://api-free.deepl.com/v2/",
-	"MICROSOFT_TRANSLATE": "https://api.cognitive.microsofttranslator.com/translate?api-version=3.0",
-	"PAPAGO":              "https://papago.naver.com/",
-	"PAPAGO_API":          "https://openapi.naver.com/v1/papago/n2mt",
-	"LIBRE":               "https://libretranslate.com/translate",
-	"LIBRE_FREE":          "https://libretranslate.de/translate",
-	"TENENT":              "https://tmt.tencentcloudapi.com",
-	"BAIDU":               "https://fanyi-api.baidu.com/api/trans/vip/translate",
-	"APERTIUM":            "https://www.apertium.org/apy/translate",
-	"MYMEMORY":            "https://api.mymemory.translated.net/get",
+	"GOOGLE_TRANSLATE":     "https://translate.google.com/m",
+	"GOOGLE_TRANSLATE_API": "https://translate.googleapis.com/translate_a/single",
+	"GOOGLE_CLOUD":         "https://translation.googleapis.com/",
+	"PONS":                 "https://en.pons.com/translate/",
+	"PONS_API":             "https://api.pons.com/v1/",
+	"YANDEX":               "https://translate.yandex.net/api/{version}/tr.json/{endpoint}",
+	"LINGUEE":              "https://www.linguee.com/",
+	"QCRI":                 "https://mt.qcri.org/api/v1/{endpoint}?",
+	"DEEPL":                "https://api.deepl.com/v2/",
+	"DEEPL_FREE":           "https://api-free.deepl.com/v2/",
+	"MICROSOFT_TRANSLATE":  "https://api.cognitive.microsofttranslator.com/translate?api-version=3.0",
+	"PAPAGO":               "https://papago.naver.com/",
+	"PAPAGO_API":           "https://openapi.naver.com/v1/papago/n2mt",
+	"LIBRE":                "https://libretranslate.com/translate",
+	"LIBRE_FREE":           "https://libretranslate.de/translate",
+	"TENENT":               "https://tmt.tencentcloudapi.com",
+	"BAIDU":                "https://fanyi-api.baidu.com/api/trans/vip/translate",
+	"APERTIUM":             "https://www.apertium.org/apy/translate",
+	"MYMEMORY":             "https://api.mymemory.translated.net/get",
 }
 
 var GOOGLE_LANGUAGES_TO_CODES = map[string]string{
@@ -657,3 +660,70 @@ var BAIDU_LANGUAGE_TO_CODE = map[string]string{
 	"vietnamese":            "vie",
 	"yueyu":                 "yue",
 }
+
+var APERTIUM_ISO_639_1_TO_3 = map[string]string{
+	"af": "afr",
+	"an": "arg",
+	"ar": "ara",
+	"be": "bel",
+	"bg": "bul",
+	"br": "bre",
+	"bs": "bos",
+	"ca": "cat",
+	"cs": "ces",
+	"cy": "cym",
+	"da": "dan",
+	"de": "deu",
+	"el": "ell",
+	"en": "eng",
+	"eo": "epo",
+	"es": "spa",
+	"et": "est",
+	"eu": "eus",
+	"fa": "fas",
+	"fi": "fin",
+	"fo": "fao",
+	"fr": "fra",
+	"ga": "gle",
+	"gl": "glg",
+	"gv": "glv",
+	"he": "heb",
+	"hi": "hin",
+	"hr": "hrv",
+	"ht": "hat",
+	"hu": "hun",
+	"id": "ind",
+	"is": "isl",
+	"it": "ita",
+	"kk": "kaz",
+	"ky": "kir",
+	"la": "lat",
+	"lt": "lit",
+	"lv": "lav",
+	"mk": "mkd",
+	"ms": "msa",
+	"mt": "mlt",
+	"nb": "nob",
+	"nl": "nld",
+	"nn": "nno",
+	"no": "nor",
+	"oc": "oci",
+	"pl": "pol",
+	"pt": "por",
+	"ro": "ron",
+	"ru": "rus",
+	"sc": "srd",
+	"se": "sme",
+	"sk": "slk",
+	"sl": "slv",
+	"sq": "sqi",
+	"sr": "srp",
+	"sv": "swe",
+	"sw": "swa",
+	"tr": "tur",
+	"tt": "tat",
+	"uk": "ukr",
+	"ur": "urd",
+	"uz": "uzb",
+	"zh": "zho",
+}
@@ -5,42 +5,47 @@ import (
 	"log"
 )
 
+var (
+	ErrRequest                       = errors.New("request error, please try again later")
+	ErrTooManyRequests               = errors.New("too many requests, please try again later")
+	ErrTranslationNotFound           = errors.New("translation not found")
+	ErrInvalidSourceOrTargetLanguage = errors.New("invalid source or target language")
+	ErrLanguageNotSupported          = errors.New("language not supported")
+	ErrInvalidPayloadKey             = errors.New("invalid payload key")
+	ErrTooLongText                   = errors.New("text is too long")
+	ErrSameSourceTarget              = errors.New("source and target languages are the same")
+	ErrUnauthorized                  = errors.New("missing or invalid credentials")
+	ErrQuotaExceeded                 = errors.New("quota exceeded")
+)
+
 func RequestError() {
-	err := errors.New("request error, please try again later")
-	log.Panic(err)
+	log.Panic(ErrRequest)
 }
 
 func TooManyRequestsError() {
-	err := errors.New("too many requests, please try again later")
-	log.Panic(err)
+	log.Panic(ErrTooManyRequests)
 }
 
 func TranslationNotFoundError() {
-	err := errors.New("translation not found")
-	log.Panic(err)
+	log.Panic(ErrTranslationNotFound)
 }
 
 func InvalidSourceOrTargetLanguageError() {
-	err := errors.New("invalid source or target language")
-	log.Panic(err)
+	log.Panic(ErrInvalidSourceOrTargetLanguage)
 }
 
 func LanguageNotSupportedExceptionError() {
-	err := errors.New("language not supported")
-	log.Panic(err)
+	log.Panic(ErrLanguageNotSupported)
 }
 
 func InvalidPayloadKeyError() {
-	err := errors.New("invalid payload key")
-	log.Panic(err)
+	log.Panic(ErrInvalidPayloadKey)
 }
 
 func TooLongTextError() {
-	err := errors.New("text is too long")
-	log.Panic(err)
+	log.Panic(ErrTooLongText)
 }
 
 func SameSourceTargetError() {
-	err := errors.New("source and target languages are the same")
-	log.Panic(err)
+	log.Panic(ErrSameSourceTarget)
 }
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/kashari/go-translate/constants"
 	"github.com/kashari/go-translate/translator"
 )
 
@@ -12,8 +17,15 @@ func main() {
 	to := flag.String("to", "fr", "target language")
 	text := flag.String("text", "Hello, World!", "text to translate")
 	isFile := flag.String("file", "", "file to translate")
+	glossary := flag.String("deepl-glossary", "", "TSV or CSV glossary file to sync with DeepL, using the DEEPL_API_KEY key")
+	glossaryName := flag.String("deepl-glossary-name", "", "name of the synced DeepL glossary, defaults to the file name")
 	flag.Parse()
 
+	if *glossary != "" {
+		syncGlossary(*from, *to, *glossary, *glossaryName)
+		return
+	}
+
 	t := translator.NewGoogleTranslator(*from, *to, nil)
 
 	if *isFile != "" {
@@ -33,3 +45,26 @@ func main() {
 
 	fmt.Println(translated)
 }
+
+// Updates the DeepL glossary when the local file changed since the last sync.
+func syncGlossary(from, to, path, name string) {
+	apiKey := os.Getenv(constants.DEEPL_ENV_VAR)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	// DeepL Free API keys end with ":fx"
+	t := translator.NewDeepLTranslator(strings.HasSuffix(apiKey, ":fx"), apiKey, from, to, nil)
+
+	glossary, updated, err := t.SyncGlossary(context.Background(), name, path)
+	if err != nil {
+		panic(err)
+	}
+
+	if updated {
+		fmt.Printf("Glossary %s updated with %d entries, id %s \n", glossary.Name, glossary.EntryCount, glossary.ID)
+		return
+	}
+
+	fmt.Printf("Glossary %s is up to date, id %s \n", glossary.Name, glossary.ID)
+}
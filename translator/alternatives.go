@@ -0,0 +1,41 @@
+package translator
+
+import "context"
+
+// Represents a single candidate translation.
+// Score is only set when the provider exposes one, otherwise it is zero.
+type Alternative struct {
+	Text  string
+	Score float64
+}
+
+// Implemented by translators that can return several ranked candidates for the same text.
+type AlternativesTranslator interface {
+	Alternatives(ctx context.Context, text string, n int) ([]Alternative, error)
+}
+
+// Implemented by translators that can translate several texts with a single call.
+type BatchTranslator interface {
+	TranslateBatch(texts []string) ([]string, error)
+}
+
+// Keeps the first n candidates, n <= 0 keeps all of them.
+func limitAlternatives(alternatives []Alternative, n int) []Alternative {
+	if n > 0 && len(alternatives) > n {
+		return alternatives[:n]
+	}
+	return alternatives
+}
+
+// Appends the text as a new candidate unless it is empty or already present.
+func appendAlternative(alternatives []Alternative, text string, score float64) []Alternative {
+	if text == "" {
+		return alternatives
+	}
+	for _, alternative := range alternatives {
+		if alternative.Text == text {
+			return alternatives
+		}
+	}
+	return append(alternatives, Alternative{Text: text, Score: score})
+}
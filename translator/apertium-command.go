@@ -0,0 +1,205 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Translates offline with the apertium command of a local Apertium installation.
+type ApertiumCommand struct {
+	command     string
+	dataDir     string
+	source      string
+	target      string
+	keepUnknown bool
+
+	modeMu sync.Mutex
+	mode   string
+}
+
+// Creates a new instance of ApertiumCommand running the apertium command found on the PATH.
+// The languages can be given as ISO 639-1 or ISO 639-3 codes.
+func NewApertiumCommand(source, target string) *ApertiumCommand {
+	return &ApertiumCommand{
+		command: "apertium",
+		source:  source,
+		target:  target,
+	}
+}
+
+// Sets the path of the apertium executable.
+func (a *ApertiumCommand) SetCommand(command string) {
+	a.command = command
+}
+
+// Sets the directory the language pairs are installed in, passed to apertium with -d.
+func (a *ApertiumCommand) SetDataDir(dir string) {
+	a.dataDir = dir
+}
+
+// Controls whether the words Apertium does not know are prefixed with an asterisk in the
+// translations, which is the default.
+func (a *ApertiumCommand) SetMarkUnknown(mark bool) {
+	a.keepUnknown = !mark
+}
+
+func (a *ApertiumCommand) Translate(text string) (string, error) {
+	var out bytes.Buffer
+	if err := a.TranslateStream(context.Background(), strings.NewReader(text), &out); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// Translates the texts with a single apertium process, the texts being separated by null
+// characters in null-flush mode.
+func (a *ApertiumCommand) TranslateBatch(batch []string) ([]string, error) {
+	if len(batch) == 0 {
+		return nil, nil
+	}
+
+	var in bytes.Buffer
+	for _, text := range batch {
+		in.WriteString(strings.ReplaceAll(text, "\x00", ""))
+		in.WriteByte(0)
+	}
+
+	var out bytes.Buffer
+	if err := a.run(context.Background(), &in, &out, "-z"); err != nil {
+		return nil, err
+	}
+
+	translations := strings.Split(strings.TrimSuffix(out.String(), "\x00"), "\x00")
+	if len(translations) != len(batch) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(batch), len(translations))
+	}
+	return translations, nil
+}
+
+func (a *ApertiumCommand) TranslateFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var out bytes.Buffer
+	if err := a.TranslateStream(context.Background(), file, &out); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// Streams the text read from r through apertium and writes the translation to w.
+func (a *ApertiumCommand) TranslateStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	return a.run(ctx, r, w)
+}
+
+// Lists the translation modes installed locally, with the codes normalized to ISO 639-3.
+func (a *ApertiumCommand) ListPairs(ctx context.Context) ([]ApertiumPair, error) {
+	modes, err := a.modes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]ApertiumPair, 0, len(modes))
+	for _, mode := range modes {
+		source, target, _ := strings.Cut(mode, "-")
+		pairs = append(pairs, ApertiumPair{Source: apertiumCode(source), Target: apertiumCode(target)})
+	}
+	return pairs, nil
+}
+
+// Returns the installed translation modes as listed by apertium -l, such as en-es.
+func (a *ApertiumCommand) modes(ctx context.Context) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.command, append(a.dataDirArgs(), "-l")...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, commandError(err, &stderr)
+	}
+
+	var modes []string
+	for _, line := range strings.Fields(stdout.String()) {
+		// other modes, such as en-es-anmor or en-morph, are not translation directions
+		if parts := strings.Split(line, "-"); len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			modes = append(modes, line)
+		}
+	}
+	return modes, nil
+}
+
+// Returns the installed mode translating from the source to the target language,
+// looked up on the first call.
+func (a *ApertiumCommand) translationMode(ctx context.Context) (string, error) {
+	a.modeMu.Lock()
+	defer a.modeMu.Unlock()
+	if a.mode != "" {
+		return a.mode, nil
+	}
+
+	modes, err := a.modes(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	source, target := apertiumCode(a.source), apertiumCode(a.target)
+	for _, mode := range modes {
+		modeSource, modeTarget, _ := strings.Cut(mode, "-")
+		if apertiumCode(modeSource) == source && apertiumCode(modeTarget) == target {
+			a.mode = mode
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("no installed apertium mode for %s-%s: %w", a.source, a.target, errs.ErrLanguageNotSupported)
+}
+
+// Runs apertium in the mode of the languages, reading the text from r and writing the translation to w.
+func (a *ApertiumCommand) run(ctx context.Context, r io.Reader, w io.Writer, flags ...string) error {
+	mode, err := a.translationMode(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := append(a.dataDirArgs(), flags...)
+	if a.keepUnknown {
+		args = append(args, "-u")
+	}
+	args = append(args, mode)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.command, args...)
+	cmd.Stdin = r
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return commandError(err, &stderr)
+	}
+	return nil
+}
+
+func (a *ApertiumCommand) dataDirArgs() []string {
+	if a.dataDir == "" {
+		return nil
+	}
+	return []string{"-d", a.dataDir}
+}
+
+// Adds what the command wrote to stderr to the error of a failed run.
+func commandError(err error, stderr *bytes.Buffer) error {
+	if message := strings.TrimSpace(stderr.String()); message != "" {
+		return fmt.Errorf("apertium failed: %w: %s", err, message)
+	}
+	return fmt.Errorf("apertium failed: %w", err)
+}
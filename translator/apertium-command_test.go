@@ -0,0 +1,97 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Both backends are interchangeable.
+var _ ApertiumBackend = (*ApertiumTranslator)(nil)
+
+// Installs a fake apertium on the PATH listing a few modes and upper-casing its input.
+// The arguments of the last translation are written to the returned file.
+func installFakeApertium(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake apertium command is a shell script")
+	}
+
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := `#!/bin/sh
+if [ "$1" = "-l" ]; then
+	printf 'en-es\neng-cat\nen-es-anmor\n'
+	exit 0
+fi
+echo "$@" > "` + argsFile + `"
+tr '[:lower:]' '[:upper:]'
+`
+	if err := os.WriteFile(filepath.Join(dir, "apertium"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return argsFile
+}
+
+func TestApertiumCommandTranslate(t *testing.T) {
+	argsFile := installFakeApertium(t)
+
+	var backend ApertiumBackend = NewApertiumCommand("en", "es")
+	backend.SetMarkUnknown(false)
+
+	translation, err := backend.Translate("hello world")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if translation != "HELLO WORLD" {
+		t.Fatalf("expected 'HELLO WORLD', got %q", translation)
+	}
+
+	args, _ := os.ReadFile(argsFile)
+	if strings.TrimSpace(string(args)) != "-u en-es" {
+		t.Fatalf("expected '-u en-es', got %q", args)
+	}
+
+	translations, err := backend.TranslateBatch([]string{"one", "two\nlines", ""})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(translations) != 3 || translations[0] != "ONE" || translations[1] != "TWO\nLINES" || translations[2] != "" {
+		t.Fatalf("unexpected translations %q", translations)
+	}
+
+	args, _ = os.ReadFile(argsFile)
+	if strings.TrimSpace(string(args)) != "-z -u en-es" {
+		t.Fatalf("expected the null-flush mode, got %q", args)
+	}
+}
+
+func TestApertiumCommandModes(t *testing.T) {
+	installFakeApertium(t)
+
+	pairs, err := NewApertiumCommand("en", "es").ListPairs(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []ApertiumPair{{Source: "eng", Target: "spa"}, {Source: "eng", Target: "cat"}}
+	if len(pairs) != len(expected) || pairs[0] != expected[0] || pairs[1] != expected[1] {
+		t.Fatalf("expected %+v, got %+v", expected, pairs)
+	}
+
+	// eng-cat is installed with ISO 639-3 codes
+	if _, err := NewApertiumCommand("en", "ca").Translate("hi"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := NewApertiumCommand("en", "fr").Translate("hi"); !errors.Is(err, errs.ErrLanguageNotSupported) {
+		t.Fatalf("expected ErrLanguageNotSupported, got %v", err)
+	}
+}
@@ -1,59 +1,96 @@
 package translator
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/kashari/go-translate/constants"
+	errs "github.com/kashari/go-translate/errors"
 )
 
+// Translates with Apertium, either through an APy server or the local apertium command.
+type ApertiumBackend interface {
+	Translate(text string) (string, error)
+	TranslateBatch(batch []string) ([]string, error)
+	TranslateFile(path string) (string, error)
+	ListPairs(ctx context.Context) ([]ApertiumPair, error)
+	SetMarkUnknown(mark bool)
+}
+
 type ApertiumTranslator struct {
-	baseURL string
-	source  string
-	target  string
-	proxies *url.URL
-	client  *http.Client
+	baseURL     string
+	source      string
+	target      string
+	proxies     *url.URL
+	client      *http.Client
+	keepUnknown bool
+}
+
+// A translation direction installed on the APy server, with ISO 639-3 codes such as eng and spa.
+type ApertiumPair struct {
+	Source string `json:"sourceLanguage"`
+	Target string `json:"targetLanguage"`
+}
+
+// The morphological analyses of a surface form, such as cat<n><pl> for cats.
+type ApertiumAnalysis struct {
+	Surface  string
+	Analyses []string
+}
+
+// A surface form generated from a morphological analysis.
+type ApertiumGeneration struct {
+	Analysis string
+	Form     string
 }
 
+// Creates a new instance of ApertiumTranslator using the public APy server of apertium.org.
+// The languages can be given as ISO 639-1 or ISO 639-3 codes.
 func NewApertiumTranslator(source, target string, proxies *url.URL) *ApertiumTranslator {
 	return &ApertiumTranslator{
-		baseURL: constants.BASE_URLS["APERTIUM"],
+		baseURL: strings.TrimSuffix(constants.BASE_URLS["APERTIUM"], "translate"),
 		source:  source,
 		target:  target,
 		proxies: proxies,
-		client:  &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxies)}},
+		client:  newHTTPClient(proxies),
 	}
 }
 
-func (a *ApertiumTranslator) Translate(text string) (string, error) {
+// Sets the root URL of the APy server, such as http://localhost:2737 for a local one.
+func (a *ApertiumTranslator) SetBaseURL(baseURL string) {
+	a.baseURL = strings.TrimRight(baseURL, "/") + "/"
+}
 
-	url := a.baseURL + "?langpair=" + a.source + "|" + a.target + "&q=" + url.QueryEscape(text)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Panic(err)
-	}
+// Controls whether the words Apertium does not know are prefixed with an asterisk in the
+// translations, which is the default.
+func (a *ApertiumTranslator) SetMarkUnknown(mark bool) {
+	a.keepUnknown = !mark
+}
 
-	req.Header.Set("Content-Type", "application/json")
+func (a *ApertiumTranslator) Translate(text string) (string, error) {
+	return a.translate(context.Background(), text)
+}
 
-	resp, err := a.client.Do(req)
-	if err != nil {
-		log.Panic(err)
+func (a *ApertiumTranslator) translate(ctx context.Context, text string) (string, error) {
+	form := url.Values{}
+	form.Set("langpair", apertiumCode(a.source)+"|"+apertiumCode(a.target))
+	form.Set("q", text)
+	if a.keepUnknown {
+		form.Set("markUnknown", "no")
 	}
 
-	defer resp.Body.Close()
-
 	var response struct {
 		ResponseData struct {
 			TranslatedText string `json:"translatedText"`
 		} `json:"responseData"`
 	}
-
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		log.Panic(err)
+	if err := a.do(ctx, "POST", "translate", form, &response); err != nil {
+		return "", err
 	}
 
 	return response.ResponseData.TranslatedText, nil
@@ -85,3 +122,143 @@ func (bt *ApertiumTranslator) TranslateFile(path string) (string, error) {
 
 	return translatedText, nil
 }
+
+// Fetches the translation pairs installed on the server.
+func (a *ApertiumTranslator) ListPairs(ctx context.Context) ([]ApertiumPair, error) {
+	var response struct {
+		ResponseData []ApertiumPair `json:"responseData"`
+	}
+	if err := a.do(ctx, "GET", "listPairs", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.ResponseData, nil
+}
+
+// Reports whether the server can translate from the source to the target language,
+// given as ISO 639-1 or ISO 639-3 codes.
+func (a *ApertiumTranslator) IsPairSupported(ctx context.Context, source, target string) (bool, error) {
+	pairs, err := a.ListPairs(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	source, target = apertiumCode(source), apertiumCode(target)
+	for _, pair := range pairs {
+		if pair.Source == source && pair.Target == target {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Returns the morphological analyses of every word of the text in the given language.
+func (a *ApertiumTranslator) Analyze(ctx context.Context, language, text string) ([]ApertiumAnalysis, error) {
+	form := url.Values{}
+	form.Set("lang", apertiumCode(language))
+	form.Set("q", text)
+
+	var response [][2]string
+	if err := a.do(ctx, "POST", "analyze", form, &response); err != nil {
+		return nil, err
+	}
+
+	analyses := make([]ApertiumAnalysis, 0, len(response))
+	for _, item := range response {
+		// items look like ["^cats/cat<n><pl>$", "cats"]
+		parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(item[0], "^"), "$"), "/")
+		analyses = append(analyses, ApertiumAnalysis{Surface: item[1], Analyses: parts[1:]})
+	}
+
+	return analyses, nil
+}
+
+// Generates the surface forms of morphological analyses in the given language,
+// such as ^cat<n><pl>$ generating cats.
+func (a *ApertiumTranslator) Generate(ctx context.Context, language, analyses string) ([]ApertiumGeneration, error) {
+	form := url.Values{}
+	form.Set("lang", apertiumCode(language))
+	form.Set("q", analyses)
+
+	var response [][2]string
+	if err := a.do(ctx, "POST", "generate", form, &response); err != nil {
+		return nil, err
+	}
+
+	generations := make([]ApertiumGeneration, 0, len(response))
+	for _, item := range response {
+		generations = append(generations, ApertiumGeneration{Form: item[0], Analysis: item[1]})
+	}
+
+	return generations, nil
+}
+
+// Guesses the language of the text, returning the score of every candidate by ISO 639-3 code.
+func (a *ApertiumTranslator) IdentifyLanguage(ctx context.Context, text string) (map[string]float64, error) {
+	form := url.Values{}
+	form.Set("q", text)
+
+	var scores map[string]float64
+	if err := a.do(ctx, "POST", "identifyLang", form, &scores); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// Sends a request to APy and decodes the JSON response into out. The form is sent as the body
+// of POST requests and as the query of the other ones.
+func (a *ApertiumTranslator) do(ctx context.Context, method, path string, form url.Values, out interface{}) error {
+	var req *http.Request
+	var err error
+	if method == "POST" {
+		req, err = http.NewRequestWithContext(ctx, method, a.baseURL+path, strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, a.baseURL+path+"?"+form.Encode(), nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Message     string `json:"message"`
+			Explanation string `json:"explanation"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+
+		message := response.Explanation
+		if message == "" {
+			message = response.Message
+		}
+		statusErr := newStatusError(resp, message)
+		if resp.StatusCode == http.StatusBadRequest && strings.Contains(message, "not installed") {
+			return fmt.Errorf("%w: %w", errs.ErrLanguageNotSupported, statusErr)
+		}
+		return statusErr
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Returns the ISO 639-3 code APy expects for a language given as ISO 639-1 code,
+// keeping variants such as the _valencia of cat_valencia.
+func apertiumCode(language string) string {
+	base, variant, found := strings.Cut(language, "_")
+	if code, ok := constants.APERTIUM_ISO_639_1_TO_3[strings.ToLower(base)]; ok {
+		base = code
+	}
+	if found {
+		return base + "_" + variant
+	}
+	return base
+}
@@ -0,0 +1,101 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Starts a fake APy server with the eng-spa pair installed.
+func newFakeApy(tb testing.TB) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/translate":
+			if r.FormValue("langpair") != "eng|spa" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"status":"error","code":400,"message":"Bad Request","explanation":"That pair is not installed"}`))
+				return
+			}
+			translation := "*Hola mundo"
+			if r.FormValue("markUnknown") == "no" {
+				translation = "Hola mundo"
+			}
+			w.Write([]byte(`{"responseData":{"translatedText":"` + translation + `"},"responseDetails":null,"responseStatus":200}`))
+		case "/listPairs":
+			w.Write([]byte(`{"responseData":[{"sourceLanguage":"eng","targetLanguage":"spa"},` +
+				`{"sourceLanguage":"cat","targetLanguage":"cat_valencia"}],"responseDetails":null,"responseStatus":200}`))
+		case "/analyze":
+			w.Write([]byte(`[["^cats/cat<n><pl>$","cats"],["^./.<sent>$","."]]`))
+		case "/generate":
+			w.Write([]byte(`[["cats","^cat<n><pl>$"]]`))
+		case "/identifyLang":
+			w.Write([]byte(`{"spa":0.92,"cat":0.05}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	tb.Cleanup(server.Close)
+	return server
+}
+
+func TestApertiumTranslate(t *testing.T) {
+	a := NewApertiumTranslator("en", "es", nil)
+	a.SetBaseURL(newFakeApy(t).URL)
+
+	translation, err := a.Translate("Hello world")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if translation != "*Hola mundo" {
+		t.Fatalf("expected the unknown word to be marked, got %s", translation)
+	}
+
+	a.SetMarkUnknown(false)
+	if translation, _ = a.Translate("Hello world"); translation != "Hola mundo" {
+		t.Fatalf("expected 'Hola mundo', got %s", translation)
+	}
+
+	a = NewApertiumTranslator("en", "fr", nil)
+	a.SetBaseURL(newFakeApy(t).URL)
+	if _, err := a.Translate("Hello"); !errors.Is(err, errs.ErrLanguageNotSupported) {
+		t.Fatalf("expected ErrLanguageNotSupported, got %v", err)
+	}
+}
+
+func TestApertiumPairsAndMorphology(t *testing.T) {
+	a := NewApertiumTranslator("en", "es", nil)
+	a.SetBaseURL(newFakeApy(t).URL)
+	ctx := context.Background()
+
+	for _, pair := range [][2]string{{"en", "es"}, {"eng", "spa"}, {"ca", "cat_valencia"}} {
+		supported, err := a.IsPairSupported(ctx, pair[0], pair[1])
+		if err != nil || !supported {
+			t.Fatalf("expected %s-%s to be supported, got %v %v", pair[0], pair[1], supported, err)
+		}
+	}
+	if supported, _ := a.IsPairSupported(ctx, "es", "en"); supported {
+		t.Fatalf("expected es-en not to be supported")
+	}
+
+	analyses, err := a.Analyze(ctx, "en", "cats.")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if analyses[0].Surface != "cats" || len(analyses[0].Analyses) != 1 || analyses[0].Analyses[0] != "cat<n><pl>" {
+		t.Fatalf("unexpected analyses %+v", analyses)
+	}
+
+	generations, err := a.Generate(ctx, "en", "^cat<n><pl>$")
+	if err != nil || generations[0].Form != "cats" {
+		t.Fatalf("unexpected generations %+v %v", generations, err)
+	}
+
+	scores, err := a.IdentifyLanguage(ctx, "Hola")
+	if err != nil || scores["spa"] != 0.92 {
+		t.Fatalf("unexpected scores %v %v", scores, err)
+	}
+}
@@ -0,0 +1,135 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long before its expiry a cached access token is renewed.
+const azureTokenMargin = time.Minute
+
+// Authenticates the requests sent to the Translator API.
+type AzureAuth interface {
+	Authorize(ctx context.Context, req *http.Request) error
+}
+
+// Authenticates with the key of a Translator resource. The region is required for regional
+// and multi-service resources and must be empty for global ones.
+type AzureSubscriptionKey struct {
+	Key    string
+	Region string
+}
+
+func (k AzureSubscriptionKey) Authorize(ctx context.Context, req *http.Request) error {
+	if k.Key == "" {
+		return errors.New("azure subscription key is required")
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", k.Key)
+	if k.Region != "" {
+		req.Header.Set("Ocp-Apim-Subscription-Region", k.Region)
+	}
+	return nil
+}
+
+// Authenticates with Microsoft Entra ID access tokens, obtained from the refresh callback
+// whenever the cached token is missing or about to expire.
+// Custom-domain endpoints only need the token, regional and global endpoints also need the
+// resource ID and region of the Translator resource.
+type AzureBearerToken struct {
+	Refresh    func(ctx context.Context) (token string, expiresAt time.Time, err error)
+	ResourceID string
+	Region     string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (b *AzureBearerToken) Authorize(ctx context.Context, req *http.Request) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.token == "" || time.Now().Add(azureTokenMargin).After(b.expiresAt) {
+		if b.Refresh == nil {
+			return errors.New("azure access token expired and cannot be refreshed")
+		}
+		token, expiresAt, err := b.Refresh(ctx)
+		if err != nil {
+			return err
+		}
+		b.token, b.expiresAt = token, expiresAt
+	}
+
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	if b.ResourceID != "" {
+		req.Header.Set("Ocp-Apim-ResourceId", b.ResourceID)
+	}
+	if b.Region != "" {
+		req.Header.Set("Ocp-Apim-Subscription-Region", b.Region)
+	}
+	return nil
+}
+
+// Exchanges a subscription key for access tokens at the token-issuing endpoint,
+// caching every token for most of its ten minutes of validity.
+type AzureTokenExchange struct {
+	// The issueToken URL, derived from the region when empty.
+	Endpoint string
+	Key      string
+	Region   string
+	Client   *http.Client
+
+	bearer AzureBearerToken
+	once   sync.Once
+}
+
+func (e *AzureTokenExchange) Authorize(ctx context.Context, req *http.Request) error {
+	e.once.Do(func() {
+		e.bearer.Refresh = e.issueToken
+		e.bearer.Region = e.Region
+	})
+	return e.bearer.Authorize(ctx, req)
+}
+
+func (e *AzureTokenExchange) issueToken(ctx context.Context) (string, time.Time, error) {
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.cognitive.microsoft.com/sts/v1.0/issueToken"
+		if e.Region != "" {
+			endpoint = "https://" + e.Region + ".api.cognitive.microsoft.com/sts/v1.0/issueToken"
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", e.Key)
+
+	client := e.Client
+	if client == nil {
+		client = newHTTPClient(nil)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, newStatusError(resp, "token exchange failed")
+	}
+
+	token, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return strings.TrimSpace(string(token)), time.Now().Add(10 * time.Minute), nil
+}
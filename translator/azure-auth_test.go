@@ -0,0 +1,98 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kashari/go-translate/constants"
+)
+
+func TestAzureKeyFromEnvironment(t *testing.T) {
+	t.Setenv(constants.MICROSOFT_ENV_VAR, "key")
+
+	var requests int64
+	a := NewAzureTranslator("en", "fr", nil, "", "")
+	a.SetEndpoint(newFakeAzure(t, &requests).URL + "/")
+
+	translation, err := a.Translate("hello")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if translation != "HELLO" {
+		t.Fatalf("expected 'HELLO', got %s", translation)
+	}
+}
+
+func TestAzureCustomDomainWithBearerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translator/text/v3.0/translate" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected the bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`[{"translations":[{"text":"Bonjour","to":"fr"}]}]`))
+	}))
+	defer server.Close()
+
+	refreshes := 0
+	a := NewAzureTranslator("en", "fr", nil, "unused", "")
+	a.SetEndpoint(server.URL + "/translator/text/v3.0")
+	a.SetAuth(&AzureBearerToken{
+		Refresh: func(ctx context.Context) (string, time.Time, error) {
+			refreshes++
+			return "token", time.Now().Add(time.Hour), nil
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := a.Translate("Hello"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if refreshes != 1 {
+		t.Fatalf("expected the token to be fetched once, got %d", refreshes)
+	}
+}
+
+func TestAzureTokenExchange(t *testing.T) {
+	issued := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sts/v1.0/issueToken":
+			if r.Header.Get("Ocp-Apim-Subscription-Key") != "key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			issued++
+			w.Write([]byte("issued-token"))
+		case "/translate":
+			if r.Header.Get("Authorization") != "Bearer issued-token" {
+				t.Errorf("expected the issued token, got %q", r.Header.Get("Authorization"))
+			}
+			if r.Header.Get("Ocp-Apim-Subscription-Region") != "westeurope" {
+				t.Errorf("expected the region header, got %q", r.Header.Get("Ocp-Apim-Subscription-Region"))
+			}
+			w.Write([]byte(`[{"translations":[{"text":"Bonjour","to":"fr"}]}]`))
+		}
+	}))
+	defer server.Close()
+
+	a := NewAzureTranslator("en", "fr", nil, "", "")
+	a.SetEndpoint(server.URL)
+	a.SetAuth(&AzureTokenExchange{Endpoint: server.URL + "/sts/v1.0/issueToken", Key: "key", Region: "westeurope"})
+
+	for i := 0; i < 2; i++ {
+		if _, err := a.Translate("Hello"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if issued != 1 {
+		t.Fatalf("expected a single token exchange, got %d", issued)
+	}
+}
@@ -0,0 +1,145 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Most elements accepted by the transliterate and dictionary endpoints in a single request.
+const azureMaxDictionaryTexts = 10
+
+// A text converted to another script.
+type AzureTransliteration struct {
+	Text   string `json:"text"`
+	Script string `json:"script"`
+}
+
+// The dictionary translations of a word or short phrase.
+type AzureDictionaryEntry struct {
+	NormalizedSource string                       `json:"normalizedSource"`
+	DisplaySource    string                       `json:"displaySource"`
+	Translations     []AzureDictionaryTranslation `json:"translations"`
+}
+
+type AzureDictionaryTranslation struct {
+	NormalizedTarget string                 `json:"normalizedTarget"`
+	DisplayTarget    string                 `json:"displayTarget"`
+	PosTag           string                 `json:"posTag"`
+	Confidence       float64                `json:"confidence"`
+	PrefixWord       string                 `json:"prefixWord"`
+	BackTranslations []AzureBackTranslation `json:"backTranslations"`
+}
+
+// A source word the translation translates back to, with how often the pair was seen.
+type AzureBackTranslation struct {
+	NormalizedText string `json:"normalizedText"`
+	DisplayText    string `json:"displayText"`
+	NumExamples    int    `json:"numExamples"`
+	FrequencyCount int    `json:"frequencyCount"`
+}
+
+// A source term and one of its dictionary translations, as used to look up examples.
+// Both should be the normalized forms returned by DictionaryLookup.
+type AzureDictionaryPair struct {
+	Text        string `json:"Text"`
+	Translation string `json:"Translation"`
+}
+
+// Example sentences using a source term and its translation.
+type AzureDictionaryExamples struct {
+	NormalizedSource string         `json:"normalizedSource"`
+	NormalizedTarget string         `json:"normalizedTarget"`
+	Examples         []AzureExample `json:"examples"`
+}
+
+// An example sentence pair, split around the term and its translation.
+type AzureExample struct {
+	SourcePrefix string `json:"sourcePrefix"`
+	SourceTerm   string `json:"sourceTerm"`
+	SourceSuffix string `json:"sourceSuffix"`
+	TargetPrefix string `json:"targetPrefix"`
+	TargetTerm   string `json:"targetTerm"`
+	TargetSuffix string `json:"targetSuffix"`
+}
+
+// Returns the full source sentence.
+func (e AzureExample) Source() string {
+	return e.SourcePrefix + e.SourceTerm + e.SourceSuffix
+}
+
+// Returns the full target sentence.
+func (e AzureExample) Target() string {
+	return e.TargetPrefix + e.TargetTerm + e.TargetSuffix
+}
+
+// Converts the texts of the given language from one script to another,
+// for example Hindi from Deva to Latn or Japanese from Jpan to Latn.
+func (a *AzureTranslator) Transliterate(ctx context.Context, texts []string, language, fromScript, toScript string) ([]AzureTransliteration, error) {
+	transliterations := make([]AzureTransliteration, 0, len(texts))
+	for start := 0; start < len(texts); start += azureMaxDictionaryTexts {
+		chunk := texts[start:min(start+azureMaxDictionaryTexts, len(texts))]
+
+		q := url.Values{}
+		q.Set("language", language)
+		q.Set("fromScript", fromScript)
+		q.Set("toScript", toScript)
+
+		var response []AzureTransliteration
+		if err := a.do(ctx, "/transliterate", q, azureTextBody(chunk), &response); err != nil {
+			return nil, err
+		}
+		if len(response) != len(chunk) {
+			return nil, fmt.Errorf("expected %d transliterations, got %d", len(chunk), len(response))
+		}
+		transliterations = append(transliterations, response...)
+	}
+
+	return transliterations, nil
+}
+
+// Looks up the dictionary translations of words or short phrases from the source to the
+// target language of the translator. The source language cannot be detected automatically.
+func (a *AzureTranslator) DictionaryLookup(ctx context.Context, texts []string) ([]AzureDictionaryEntry, error) {
+	entries := make([]AzureDictionaryEntry, 0, len(texts))
+	for start := 0; start < len(texts); start += azureMaxDictionaryTexts {
+		chunk := texts[start:min(start+azureMaxDictionaryTexts, len(texts))]
+
+		var response []AzureDictionaryEntry
+		if err := a.do(ctx, "/dictionary/lookup", a.dictionaryQuery(), azureTextBody(chunk), &response); err != nil {
+			return nil, err
+		}
+		if len(response) != len(chunk) {
+			return nil, fmt.Errorf("expected %d dictionary entries, got %d", len(chunk), len(response))
+		}
+		entries = append(entries, response...)
+	}
+
+	return entries, nil
+}
+
+// Fetches example sentences for pairs of terms and translations found with DictionaryLookup.
+func (a *AzureTranslator) DictionaryExamples(ctx context.Context, pairs []AzureDictionaryPair) ([]AzureDictionaryExamples, error) {
+	examples := make([]AzureDictionaryExamples, 0, len(pairs))
+	for start := 0; start < len(pairs); start += azureMaxDictionaryTexts {
+		chunk := pairs[start:min(start+azureMaxDictionaryTexts, len(pairs))]
+
+		var response []AzureDictionaryExamples
+		if err := a.do(ctx, "/dictionary/examples", a.dictionaryQuery(), chunk, &response); err != nil {
+			return nil, err
+		}
+		if len(response) != len(chunk) {
+			return nil, fmt.Errorf("expected %d example lists, got %d", len(chunk), len(response))
+		}
+		examples = append(examples, response...)
+	}
+
+	return examples, nil
+}
+
+func (a *AzureTranslator) dictionaryQuery() url.Values {
+	q := url.Values{}
+	q.Set("from", a.source)
+	q.Set("to", a.target)
+	return q
+}
@@ -0,0 +1,68 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAzureDictionaryEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Ocp-Apim-Subscription-Region") != "westeurope" {
+			t.Errorf("expected the region header, got %q", r.Header.Get("Ocp-Apim-Subscription-Region"))
+		}
+
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/transliterate":
+			if q.Get("language") != "hi" || q.Get("fromScript") != "Deva" || q.Get("toScript") != "Latn" {
+				t.Errorf("unexpected transliterate query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"text":"namaste","script":"Latn"}]`))
+		case "/dictionary/lookup":
+			if q.Get("from") != "en" || q.Get("to") != "es" {
+				t.Errorf("unexpected lookup query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"normalizedSource":"fly","displaySource":"fly","translations":[` +
+				`{"normalizedTarget":"volar","displayTarget":"volar","posTag":"VERB","confidence":0.45,"prefixWord":"",` +
+				`"backTranslations":[{"normalizedText":"fly","displayText":"fly","numExamples":15,"frequencyCount":4637}]}]}]`))
+		case "/dictionary/examples":
+			w.Write([]byte(`[{"normalizedSource":"fly","normalizedTarget":"volar","examples":[` +
+				`{"sourcePrefix":"They need machines to ","sourceTerm":"fly","sourceSuffix":".",` +
+				`"targetPrefix":"Necesitan máquinas para ","targetTerm":"volar","targetSuffix":"."}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	a := NewAzureTranslator("en", "es", nil, "key", "westeurope")
+	a.baseURL = server.URL
+
+	transliterations, err := a.Transliterate(context.Background(), []string{"नमस्ते"}, "hi", "Deva", "Latn")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if transliterations[0].Text != "namaste" {
+		t.Fatalf("expected 'namaste', got %s", transliterations[0].Text)
+	}
+
+	entries, err := a.DictionaryLookup(context.Background(), []string{"fly"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	translation := entries[0].Translations[0]
+	if translation.NormalizedTarget != "volar" || translation.PosTag != "VERB" || translation.BackTranslations[0].FrequencyCount != 4637 {
+		t.Fatalf("unexpected dictionary translation %+v", translation)
+	}
+
+	examples, err := a.DictionaryExamples(context.Background(), []AzureDictionaryPair{{Text: "fly", Translation: "volar"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	example := examples[0].Examples[0]
+	if example.Source() != "They need machines to fly." || example.Target() != "Necesitan máquinas para volar." {
+		t.Fatalf("unexpected example %+v", example)
+	}
+}
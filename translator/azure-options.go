@@ -0,0 +1,161 @@
+package translator
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Whether the texts are plain text or HTML.
+type AzureTextType string
+
+const (
+	AzureTextPlain AzureTextType = "plain"
+	AzureTextHTML  AzureTextType = "html"
+)
+
+// What happens to profanities in the translation.
+type AzureProfanityAction string
+
+const (
+	AzureProfanityNoAction AzureProfanityAction = "NoAction"
+	AzureProfanityMarked   AzureProfanityAction = "Marked"
+	AzureProfanityDeleted  AzureProfanityAction = "Deleted"
+)
+
+// How marked profanities are shown.
+type AzureProfanityMarker string
+
+const (
+	AzureProfanityAsterisk AzureProfanityMarker = "Asterisk"
+	AzureProfanityTag      AzureProfanityMarker = "Tag"
+)
+
+// Optional parameters of an Azure translation, the zero value leaves every one to Azure's default.
+type AzureOptions struct {
+	TextType        AzureTextType
+	ProfanityAction AzureProfanityAction
+	// Only used with AzureProfanityMarked.
+	ProfanityMarker       AzureProfanityMarker
+	IncludeAlignment      bool
+	IncludeSentenceLength bool
+	// Category of a Custom Translator model.
+	Category string
+	// Language to assume when the source language cannot be detected.
+	SuggestedFrom string
+	// Fails instead of falling back to the general model when the category has no model
+	// for the language pair.
+	DisableFallback bool
+}
+
+// Alignment of the translation with the source text, as projected by Azure.
+type AzureAlignment struct {
+	Proj string `json:"proj"`
+}
+
+// A source range of characters aligned with a target range, both ends included.
+type AzureAlignmentSpan struct {
+	SourceStart int
+	SourceEnd   int
+	TargetStart int
+	TargetEnd   int
+}
+
+// Parses the projection, formatted as "srcStart:srcEnd-tgtStart:tgtEnd" pairs separated by spaces.
+func (a AzureAlignment) Spans() ([]AzureAlignmentSpan, error) {
+	var spans []AzureAlignmentSpan
+	for _, pair := range strings.Fields(a.Proj) {
+		source, target, ok := strings.Cut(pair, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid alignment %q", pair)
+		}
+
+		var values [4]int
+		for i, part := range []string{source, target} {
+			start, end, ok := strings.Cut(part, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid alignment %q", pair)
+			}
+			var err error
+			if values[i*2], err = strconv.Atoi(start); err != nil {
+				return nil, fmt.Errorf("invalid alignment %q", pair)
+			}
+			if values[i*2+1], err = strconv.Atoi(end); err != nil {
+				return nil, fmt.Errorf("invalid alignment %q", pair)
+			}
+		}
+
+		spans = append(spans, AzureAlignmentSpan{SourceStart: values[0], SourceEnd: values[1], TargetStart: values[2], TargetEnd: values[3]})
+	}
+	return spans, nil
+}
+
+// Lengths of the sentences of the source text and of the translation.
+type AzureSentenceLength struct {
+	SourceLengths      []int `json:"srcSentLen"`
+	TranslationLengths []int `json:"transSentLen"`
+}
+
+// Checks the options against each other and against the languages of the translator.
+func (a *AzureTranslator) validateOptions(opts AzureOptions) error {
+	switch opts.TextType {
+	case "", AzureTextPlain, AzureTextHTML:
+	default:
+		return fmt.Errorf("invalid text type %q", opts.TextType)
+	}
+
+	switch opts.ProfanityAction {
+	case "", AzureProfanityNoAction, AzureProfanityDeleted:
+		if opts.ProfanityMarker != "" {
+			return fmt.Errorf("profanity marker requires the Marked profanity action")
+		}
+	case AzureProfanityMarked:
+	default:
+		return fmt.Errorf("invalid profanity action %q", opts.ProfanityAction)
+	}
+
+	switch opts.ProfanityMarker {
+	case "", AzureProfanityAsterisk, AzureProfanityTag:
+	default:
+		return fmt.Errorf("invalid profanity marker %q", opts.ProfanityMarker)
+	}
+
+	if opts.SuggestedFrom != "" && a.source != "" && a.source != "auto" {
+		return fmt.Errorf("suggested source language requires automatic detection")
+	}
+
+	if opts.DisableFallback && opts.Category == "" {
+		return fmt.Errorf("disabling the fallback requires a category")
+	}
+
+	return nil
+}
+
+// Adds the options that are set to the request query.
+func (opts AzureOptions) apply(q url.Values) {
+	if opts.TextType != "" {
+		q.Set("textType", string(opts.TextType))
+	}
+	if opts.ProfanityAction != "" {
+		q.Set("profanityAction", string(opts.ProfanityAction))
+	}
+	if opts.ProfanityMarker != "" {
+		q.Set("profanityMarker", string(opts.ProfanityMarker))
+	}
+	if opts.IncludeAlignment {
+		q.Set("includeAlignment", "true")
+	}
+	if opts.IncludeSentenceLength {
+		q.Set("includeSentenceLength", "true")
+	}
+	if opts.Category != "" {
+		q.Set("category", opts.Category)
+	}
+	if opts.SuggestedFrom != "" {
+		q.Set("suggestedFrom", opts.SuggestedFrom)
+	}
+	if opts.DisableFallback {
+		q.Set("allowFallback", "false")
+	}
+}
@@ -2,11 +2,25 @@ package translator
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kashari/go-translate/constants"
+	errs "github.com/kashari/go-translate/errors"
+)
+
+const (
+	azureGlobalEndpoint = "https://api.cognitive.microsofttranslator.com"
+	azureAPIVersion     = "3.0"
+	// Limits of a single translate request.
+	azureMaxTexts = 100
+	azureMaxChars = 50000
 )
 
 type AzureTranslator struct {
@@ -15,76 +29,264 @@ type AzureTranslator struct {
 	target  string
 	proxies *url.URL
 	client  *http.Client
-	apiKey  string
-	region  string
+	auth    AzureAuth
+	options AzureOptions
+}
+
+// A translated text together with the language Azure detected when no source was given.
+// Alignment and SentenceLength are only set when requested in the options.
+type AzureTranslation struct {
+	Text             string
+	To               string
+	DetectedLanguage *AzureDetectedLanguage
+	Alignment        *AzureAlignment
+	SentenceLength   *AzureSentenceLength
+}
+
+type AzureDetectedLanguage struct {
+	Language string  `json:"language"`
+	Score    float64 `json:"score"`
+}
+
+// Error returned by the Translator API, Code being Azure's six digit error code.
+// It unwraps to the matching errs value so callers can check it with errors.Is.
+type AzureError struct {
+	StatusCode int
+	Code       int
+	Message    string
 }
 
+func (e *AzureError) Error() string {
+	return fmt.Sprintf("azure translator error %d: %s", e.Code, e.Message)
+}
+
+func (e *AzureError) Unwrap() error {
+	switch e.Code {
+	case 400019, 400023, 400035, 400036:
+		return errs.ErrInvalidSourceOrTargetLanguage
+	case 400050, 400077, 413000:
+		return errs.ErrTooLongText
+	case 403000, 403001:
+		return errs.ErrQuotaExceeded
+	}
+
+	switch e.Code / 1000 {
+	case 401:
+		return errs.ErrUnauthorized
+	case 429:
+		return errs.ErrTooManyRequests
+	}
+
+	if e.StatusCode >= 500 {
+		return errs.ErrRequest
+	}
+	return nil
+}
+
+// Creates a new instance of AzureTranslator on the global endpoint, authenticated with the
+// subscription key. When apiKey is empty the key is read from the MICROSOFT_API_KEY
+// environment variable.
 func NewAzureTranslator(source, target string, proxies *url.URL, apiKey, region string) *AzureTranslator {
+	if apiKey == "" {
+		apiKey = os.Getenv(constants.MICROSOFT_ENV_VAR)
+	}
+
 	return &AzureTranslator{
-		baseURL: "https://api.cognitive.microsofttranslator.com/translate?api-version=3.0",
+		baseURL: azureGlobalEndpoint,
 		source:  source,
 		target:  target,
 		proxies: proxies,
-		client:  &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxies)}},
-		apiKey:  apiKey,
-		region:  region,
+		client:  newHTTPClient(proxies),
+		auth:    AzureSubscriptionKey{Key: apiKey, Region: region},
 	}
 }
 
-func (a *AzureTranslator) Translate(text string) (string, error) {
-	u, _ := url.Parse(a.baseURL)
-	q := u.Query()
-	q.Add("from", a.source)
-	q.Add("to", a.target)
-	u.RawQuery = q.Encode()
+// Sets the endpoint the requests are sent to, such as the regional
+// https://api-eur.cognitive.microsofttranslator.com or a custom domain like
+// https://my-resource.cognitiveservices.azure.com/translator/text/v3.0.
+func (a *AzureTranslator) SetEndpoint(endpoint string) {
+	a.baseURL = strings.TrimRight(endpoint, "/")
+}
 
-	body := []struct {
-		Text string
-	}{
-		{Text: text},
+// Replaces the subscription key authentication, for example with Entra ID tokens.
+// A token exchange without its own client issues tokens through the proxies of the translator.
+func (a *AzureTranslator) SetAuth(auth AzureAuth) {
+	if exchange, ok := auth.(*AzureTokenExchange); ok && exchange.Client == nil {
+		exchange.Client = a.client
 	}
-	b, _ := json.Marshal(body)
+	a.auth = auth
+}
+
+// Sets the options used by Translate, TranslateBatch and TranslateFile.
+func (a *AzureTranslator) SetOptions(opts AzureOptions) {
+	a.options = opts
+}
+
+func (a *AzureTranslator) Translate(text string) (string, error) {
+	return a.TranslateWithOptions(text, a.options)
+}
 
-	req, err := http.NewRequest("POST", u.String(), bytes.NewBuffer(b))
+// Translates the text with the given options instead of the ones of the translator.
+func (a *AzureTranslator) TranslateWithOptions(text string, opts AzureOptions) (string, error) {
+	translations, err := a.TranslateDetailed(context.Background(), []string{text}, opts)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
-	req.Header.Add("Ocp-Apim-Subscription-Key", a.apiKey)
-	req.Header.Add("Ocp-Apim-Subscription-Region", a.region)
-	req.Header.Add("Content-Type", "application/json")
 
-	res, err := http.DefaultClient.Do(req)
+	return translations[0].Text, nil
+}
+
+func (a *AzureTranslator) TranslateBatch(texts []string) ([]string, error) {
+	return a.TranslateBatchWithOptions(texts, a.options)
+}
+
+// Translates the texts with the given options instead of the ones of the translator.
+func (a *AzureTranslator) TranslateBatchWithOptions(texts []string, opts AzureOptions) ([]string, error) {
+	translations, err := a.TranslateDetailed(context.Background(), texts, opts)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	var result interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		log.Fatal(err)
+	translatedTexts := make([]string, len(translations))
+	for i, translation := range translations {
+		translatedTexts[i] = translation.Text
 	}
+	return translatedTexts, nil
+}
 
-	translations := result.([]interface{})
-	translatedText := translations[0].(map[string]interface{})["translations"].([]interface{})[0].(map[string]interface{})["text"].(string)
-	return translatedText, nil
+func (a *AzureTranslator) TranslateFile(path string) (string, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return a.Translate(string(text))
 }
 
-func (a *AzureTranslator) TranslateBatch(texts []string) ([]string, error) {
-	var translatedTexts []string
-	for _, text := range texts {
-		translatedText, err := a.Translate(text)
+// Translates the texts, packing up to 100 of them or 50000 characters into every request,
+// and returns the translations in the same order.
+func (a *AzureTranslator) TranslateDetailed(ctx context.Context, texts []string, opts AzureOptions) ([]AzureTranslation, error) {
+	if err := a.validateOptions(opts); err != nil {
+		return nil, err
+	}
+
+	chunks, err := chunkTexts(texts, azureMaxTexts, azureMaxChars, utf8.RuneCountInString)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]AzureTranslation, 0, len(texts))
+	for _, chunk := range chunks {
+		translated, err := a.translateChunk(ctx, chunk, opts)
 		if err != nil {
 			return nil, err
 		}
-		translatedTexts = append(translatedTexts, translatedText)
+		translations = append(translations, translated...)
 	}
-	return translatedTexts, nil
+
+	return translations, nil
 }
 
-func (a *AzureTranslator) TranslateFile(path string) (string, error) {
-	text, err := os.ReadFile(path)
+// Translates the texts with a single request.
+func (a *AzureTranslator) translateChunk(ctx context.Context, texts []string, opts AzureOptions) ([]AzureTranslation, error) {
+	q := url.Values{}
+	if a.source != "" && a.source != "auto" {
+		q.Set("from", a.source)
+	}
+	q.Set("to", a.target)
+	opts.apply(q)
+
+	var response []struct {
+		DetectedLanguage *AzureDetectedLanguage `json:"detectedLanguage"`
+		Translations     []struct {
+			Text           string               `json:"text"`
+			To             string               `json:"to"`
+			Alignment      *AzureAlignment      `json:"alignment"`
+			SentenceLength *AzureSentenceLength `json:"sentLen"`
+		} `json:"translations"`
+	}
+
+	if err := a.do(ctx, "/translate", q, azureTextBody(texts), &response); err != nil {
+		return nil, err
+	}
+
+	if len(response) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(response))
+	}
+
+	translations := make([]AzureTranslation, len(texts))
+	for i, result := range response {
+		if len(result.Translations) == 0 {
+			return nil, errs.ErrTranslationNotFound
+		}
+		translations[i] = AzureTranslation{
+			Text:             result.Translations[0].Text,
+			To:               result.Translations[0].To,
+			DetectedLanguage: result.DetectedLanguage,
+			Alignment:        result.Translations[0].Alignment,
+			SentenceLength:   result.Translations[0].SentenceLength,
+		}
+	}
+
+	return translations, nil
+}
+
+type azureText struct {
+	Text string `json:"Text"`
+}
+
+func azureTextBody(texts []string) []azureText {
+	body := make([]azureText, len(texts))
+	for i, text := range texts {
+		body[i] = azureText{Text: text}
+	}
+	return body
+}
+
+// Sends a request to the Translator API and decodes the JSON response into out.
+// Error responses are returned as *AzureError.
+func (a *AzureTranslator) do(ctx context.Context, path string, q url.Values, body interface{}, out interface{}) error {
+	u, err := url.Parse(a.baseURL + path)
 	if err != nil {
-		return "", err
+		return err
 	}
+	q.Set("api-version", azureAPIVersion)
+	u.RawQuery = q.Encode()
 
-	return a.Translate(string(text))
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", u.String(), bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	if err := a.auth.Authorize(ctx, req); err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var response struct {
+			Error struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(res.Body).Decode(&response)
+		code := response.Error.Code
+		if code == 0 {
+			code = res.StatusCode * 1000
+		}
+		return &AzureError{StatusCode: res.StatusCode, Code: code, Message: response.Error.Message}
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
 }
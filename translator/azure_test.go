@@ -0,0 +1,141 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Starts a fake Translator API upper-casing every text of the request.
+func newFakeAzure(tb testing.TB, requests *int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(requests, 1)
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":401000,"message":"The request is not authorized because credentials are missing or invalid."}}`))
+			return
+		}
+
+		var body []struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&body)
+
+		var response []map[string]interface{}
+		for _, item := range body {
+			response = append(response, map[string]interface{}{
+				"detectedLanguage": map[string]interface{}{"language": "en", "score": 1.0},
+				"translations":     []map[string]string{{"text": strings.ToUpper(item.Text), "to": r.URL.Query().Get("to")}},
+			})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	tb.Cleanup(server.Close)
+	return server
+}
+
+func TestAzureTranslateBatch(t *testing.T) {
+	var requests int64
+	a := NewAzureTranslator("auto", "fr", nil, "key", "westeurope")
+	a.baseURL = newFakeAzure(t, &requests).URL
+
+	texts := make([]string, 150)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+
+	translations, err := a.TranslateBatch(texts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i, translation := range translations {
+		if translation != fmt.Sprintf("TEXT %d", i) {
+			t.Fatalf("expected 'TEXT %d', got %s", i, translation)
+		}
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestAzureTranslateError(t *testing.T) {
+	var requests int64
+	a := NewAzureTranslator("en", "fr", nil, "wrong", "westeurope")
+	a.baseURL = newFakeAzure(t, &requests).URL
+
+	_, err := a.Translate("hello")
+
+	var azureErr *AzureError
+	if !errors.As(err, &azureErr) || azureErr.Code != 401000 {
+		t.Fatalf("expected an AzureError with code 401000, got %v", err)
+	}
+
+	if !errors.Is(err, errs.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestAzureTranslateWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		expected := map[string]string{
+			"textType":              "html",
+			"profanityAction":       "Marked",
+			"profanityMarker":       "Tag",
+			"includeAlignment":      "true",
+			"includeSentenceLength": "true",
+			"category":              "a2f3-general",
+			"allowFallback":         "false",
+		}
+		for key, value := range expected {
+			if q.Get(key) != value {
+				t.Errorf("expected %s=%s, got %q", key, value, q.Get(key))
+			}
+		}
+		w.Write([]byte(`[{"translations":[{"text":"Bonjour <profanity>x</profanity>","to":"fr",` +
+			`"alignment":{"proj":"0:4-0:6 6:6-8:32"},"sentLen":{"srcSentLen":[7],"transSentLen":[33]}}]}]`))
+	}))
+	defer server.Close()
+
+	a := NewAzureTranslator("en", "fr", nil, "key", "")
+	a.baseURL = server.URL
+
+	opts := AzureOptions{
+		TextType:              AzureTextHTML,
+		ProfanityAction:       AzureProfanityMarked,
+		ProfanityMarker:       AzureProfanityTag,
+		IncludeAlignment:      true,
+		IncludeSentenceLength: true,
+		Category:              "a2f3-general",
+		DisableFallback:       true,
+	}
+	translations, err := a.TranslateDetailed(context.Background(), []string{"Hello x"}, opts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	translation := translations[0]
+	if translation.SentenceLength == nil || translation.SentenceLength.TranslationLengths[0] != 33 {
+		t.Fatalf("expected the sentence lengths, got %+v", translation.SentenceLength)
+	}
+
+	spans, err := translation.Alignment.Spans()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(spans) != 2 || spans[1] != (AzureAlignmentSpan{SourceStart: 6, SourceEnd: 6, TargetStart: 8, TargetEnd: 32}) {
+		t.Fatalf("unexpected alignment spans %+v", spans)
+	}
+
+	if _, err := a.TranslateWithOptions("Hello", AzureOptions{ProfanityMarker: AzureProfanityTag}); err == nil {
+		t.Fatalf("expected an error for a marker without the Marked action, got nil")
+	}
+}
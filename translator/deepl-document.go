@@ -0,0 +1,186 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Longest wait between two status checks of a document.
+const deeplMaxPollInterval = 30 * time.Second
+
+// File types accepted by the document endpoints.
+var deeplDocumentTypes = map[string]bool{
+	".docx": true, ".pptx": true, ".pdf": true, ".html": true, ".htm": true, ".txt": true,
+}
+
+// Identifies a document uploaded to DeepL. Storing it allows resuming the workflow later,
+// even from another process, with WaitDocument and DownloadDocument.
+type DeepLDocument struct {
+	ID  string `json:"document_id"`
+	Key string `json:"document_key"`
+}
+
+// Progress of a document translation.
+type DeepLDocumentStatus struct {
+	ID               string `json:"document_id"`
+	Status           string `json:"status"`
+	SecondsRemaining int    `json:"seconds_remaining"`
+	BilledCharacters int    `json:"billed_characters"`
+	ErrorMessage     string `json:"error_message"`
+}
+
+// Reports whether the translated document is ready to be downloaded.
+func (s DeepLDocumentStatus) Done() bool {
+	return s.Status == "done"
+}
+
+// Uploads the document at the path, waits for DeepL to translate it and writes the result to w.
+func (d *DeepLTranslator) TranslateDocument(ctx context.Context, path string, w io.Writer) (*DeepLDocument, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	document, err := d.UploadDocument(ctx, file, filepath.Base(path), d.options)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, d.ResumeDocument(ctx, *document, w)
+}
+
+// Waits for an uploaded document to be translated and writes the result to w.
+func (d *DeepLTranslator) ResumeDocument(ctx context.Context, document DeepLDocument, w io.Writer) error {
+	if _, err := d.WaitDocument(ctx, document); err != nil {
+		return err
+	}
+
+	return d.DownloadDocument(ctx, document, w)
+}
+
+// Uploads a document for translation, the file name tells DeepL its type.
+// Only the formality and glossary options apply to documents.
+func (d *DeepLTranslator) UploadDocument(ctx context.Context, r io.Reader, filename string, opts DeepLOptions) (*DeepLDocument, error) {
+	if !deeplDocumentTypes[strings.ToLower(filepath.Ext(filename))] {
+		return nil, fmt.Errorf("unsupported document type %s", filename)
+	}
+	if err := d.validateOptions(opts); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if d.source != "" && d.source != "auto" {
+		params.Set("source_lang", d.source)
+	}
+	params.Set("target_lang", d.target)
+	params.Set("filename", filename)
+	if opts.Formality != "" {
+		params.Set("formality", string(opts.Formality))
+	}
+	if opts.GlossaryID != "" {
+		params.Set("glossary_id", opts.GlossaryID)
+	}
+
+	req, err := streamMultipart(ctx, d.apiURL+"document", params, filename, r)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.send(req)
+	if err != nil {
+		req.Body.Close()
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var document DeepLDocument
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// Fetches the progress of a document translation.
+func (d *DeepLTranslator) DocumentStatus(ctx context.Context, document DeepLDocument) (*DeepLDocumentStatus, error) {
+	form := url.Values{}
+	form.Set("document_key", document.Key)
+
+	var status DeepLDocumentStatus
+	if err := d.doJSON(ctx, "POST", "document/"+url.PathEscape(document.ID), form, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// Polls the status of a document until it is translated, waiting longer between every check.
+// It returns an error when the translation fails or the context is done.
+func (d *DeepLTranslator) WaitDocument(ctx context.Context, document DeepLDocument) (*DeepLDocumentStatus, error) {
+	interval := d.pollInterval
+	for {
+		status, err := d.DocumentStatus(ctx, document)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status.Status {
+		case "done":
+			return status, nil
+		case "error":
+			return nil, fmt.Errorf("document translation failed: %s", status.ErrorMessage)
+		}
+
+		wait := interval
+		if hint := time.Duration(status.SecondsRemaining) * time.Second; hint > wait {
+			wait = min(hint, deeplMaxPollInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		interval = min(interval*2, deeplMaxPollInterval)
+	}
+}
+
+// Writes the translated document to w. DeepL only serves the result once.
+func (d *DeepLTranslator) DownloadDocument(ctx context.Context, document DeepLDocument, w io.Writer) error {
+	form := url.Values{}
+	form.Set("document_key", document.Key)
+
+	resp, err := d.request(ctx, "POST", "document/"+url.PathEscape(document.ID)+"/result", form, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// Reads a file to translate as text, refusing binary content such as a document of an
+// unsupported type.
+func readTextFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0 {
+		return "", fmt.Errorf("%s is not a text file", filepath.Base(path))
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,159 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Fake of the DeepL document endpoints, translating by upper-casing the file after a few status checks.
+type fakeDocuments struct {
+	mu       sync.Mutex
+	content  string
+	checks   int
+	failWith string
+}
+
+func (f *fakeDocuments) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v2/document":
+		file, header, err := r.FormFile("file")
+		if err != nil || header.Filename != "manual.txt" || r.FormValue("target_lang") != "DE" || r.FormValue("formality") != "less" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		f.content = string(content)
+		w.Write([]byte(`{"document_id":"doc1","document_key":"secret"}`))
+	case "/v2/document/doc1":
+		if r.FormValue("document_key") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		f.checks++
+		switch {
+		case f.failWith != "":
+			w.Write([]byte(`{"document_id":"doc1","status":"error","error_message":"` + f.failWith + `"}`))
+		case f.checks < 3:
+			w.Write([]byte(`{"document_id":"doc1","status":"translating"}`))
+		default:
+			w.Write([]byte(`{"document_id":"doc1","status":"done","billed_characters":42}`))
+		}
+	case "/v2/document/doc1/result":
+		if r.FormValue("document_key") != "secret" || f.checks < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(strings.ToUpper(f.content)))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestDocumentTranslator(fake *fakeDocuments) (*DeepLTranslator, func()) {
+	server := httptest.NewServer(fake)
+	d := NewDeepLTranslator(true, "key", "EN", "DE", nil)
+	d.apiURL = server.URL + "/v2/"
+	d.pollInterval = time.Millisecond
+	d.SetOptions(DeepLOptions{Formality: DeepLFormalityLess})
+	return d, server.Close
+}
+
+func TestDeepLTranslateDocument(t *testing.T) {
+	fake := &fakeDocuments{}
+	d, stop := newTestDocumentTranslator(fake)
+	defer stop()
+
+	path := filepath.Join(t.TempDir(), "manual.txt")
+	os.WriteFile(path, []byte("press the button"), 0644)
+
+	var out bytes.Buffer
+	document, err := d.TranslateDocument(context.Background(), path, &out)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if document.ID != "doc1" || document.Key != "secret" {
+		t.Fatalf("unexpected document %+v", document)
+	}
+
+	if out.String() != "PRESS THE BUTTON" {
+		t.Fatalf("expected 'PRESS THE BUTTON', got %s", out.String())
+	}
+
+	if fake.checks != 3 {
+		t.Fatalf("expected 3 status checks, got %d", fake.checks)
+	}
+}
+
+func TestDeepLResumeDocument(t *testing.T) {
+	fake := &fakeDocuments{}
+	d, stop := newTestDocumentTranslator(fake)
+	defer stop()
+
+	document, err := d.UploadDocument(context.Background(), strings.NewReader("open the lid"), "manual.txt", d.options)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// a new translator only knowing the stored document ID and key
+	resumed, stop := newTestDocumentTranslator(fake)
+	defer stop()
+
+	var out bytes.Buffer
+	if err := resumed.ResumeDocument(context.Background(), DeepLDocument{ID: document.ID, Key: document.Key}, &out); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if out.String() != "OPEN THE LID" {
+		t.Fatalf("expected 'OPEN THE LID', got %s", out.String())
+	}
+}
+
+func TestDeepLWaitDocumentError(t *testing.T) {
+	fake := &fakeDocuments{failWith: "corrupt file"}
+	d, stop := newTestDocumentTranslator(fake)
+	defer stop()
+
+	_, err := d.WaitDocument(context.Background(), DeepLDocument{ID: "doc1", Key: "secret"})
+	if err == nil || !strings.Contains(err.Error(), "corrupt file") {
+		t.Fatalf("expected the DeepL error message, got %v", err)
+	}
+
+	if _, err := d.UploadDocument(context.Background(), strings.NewReader(""), "archive.zip", d.options); err == nil {
+		t.Fatalf("expected an error for an unsupported document type, got nil")
+	}
+}
+
+func TestDeepLTranslateFile(t *testing.T) {
+	fake := &fakeDocuments{}
+	d, stop := newTestDocumentTranslator(fake)
+	defer stop()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manual.txt")
+	os.WriteFile(path, []byte("turn it off"), 0644)
+
+	// the document types go through the document endpoints
+	translated, err := d.TranslateFile(path)
+	if err != nil || translated != "TURN IT OFF" {
+		t.Fatalf("expected 'TURN IT OFF', got %q %v", translated, err)
+	}
+
+	path = filepath.Join(dir, "logo.png")
+	os.WriteFile(path, []byte{0x89, 'P', 'N', 'G', 0, 0, 0, 0x0d}, 0644)
+	if _, err := d.TranslateFile(path); err == nil || !strings.Contains(err.Error(), "not a text file") {
+		t.Fatalf("expected an error for a binary file, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package translator
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A glossary stored on DeepL.
+type DeepLGlossary struct {
+	ID           string    `json:"glossary_id"`
+	Name         string    `json:"name"`
+	Ready        bool      `json:"ready"`
+	SourceLang   string    `json:"source_lang"`
+	TargetLang   string    `json:"target_lang"`
+	CreationTime time.Time `json:"creation_time"`
+	EntryCount   int       `json:"entry_count"`
+}
+
+// Creates a glossary for the source and target languages of the translator.
+// DeepL glossaries cannot be edited, changing one means creating it again.
+func (d *DeepLTranslator) CreateGlossary(ctx context.Context, name string, entries map[string]string) (*DeepLGlossary, error) {
+	if d.source == "" || d.source == "auto" {
+		return nil, errors.New("glossaries require an explicit source language")
+	}
+
+	tsv, err := glossaryTSV(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("name", name)
+	form.Set("source_lang", d.source)
+	form.Set("target_lang", d.target)
+	form.Set("entries", tsv)
+	form.Set("entries_format", "tsv")
+
+	var glossary DeepLGlossary
+	if err := d.doJSON(ctx, "POST", "glossaries", form, &glossary); err != nil {
+		return nil, err
+	}
+
+	return &glossary, nil
+}
+
+// Lists all the glossaries of the account.
+func (d *DeepLTranslator) ListGlossaries(ctx context.Context) ([]DeepLGlossary, error) {
+	var response struct {
+		Glossaries []DeepLGlossary `json:"glossaries"`
+	}
+	if err := d.doJSON(ctx, "GET", "glossaries", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Glossaries, nil
+}
+
+// Fetches the details of a glossary.
+func (d *DeepLTranslator) GetGlossary(ctx context.Context, glossaryID string) (*DeepLGlossary, error) {
+	var glossary DeepLGlossary
+	if err := d.doJSON(ctx, "GET", "glossaries/"+url.PathEscape(glossaryID), nil, &glossary); err != nil {
+		return nil, err
+	}
+
+	return &glossary, nil
+}
+
+// Fetches the entries of a glossary as a map from source to target term.
+func (d *DeepLTranslator) GlossaryEntries(ctx context.Context, glossaryID string) (map[string]string, error) {
+	resp, err := d.request(ctx, "GET", "glossaries/"+url.PathEscape(glossaryID)+"/entries", nil, "text/tab-separated-values")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readGlossaryEntries(resp.Body, '\t')
+}
+
+// Deletes a glossary.
+func (d *DeepLTranslator) DeleteGlossary(ctx context.Context, glossaryID string) error {
+	resp, err := d.request(ctx, "DELETE", "glossaries/"+url.PathEscape(glossaryID), nil, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Makes sure DeepL holds a glossary with the given name and the entries of the local file.
+// The glossary is only created again, replacing the previous ones with that name, when the
+// entries differ, so running it repeatedly is cheap. It reports whether DeepL was updated.
+func (d *DeepLTranslator) SyncGlossary(ctx context.Context, name, path string) (*DeepLGlossary, bool, error) {
+	entries, err := LoadGlossaryFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	glossaries, err := d.ListGlossaries(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var existing []DeepLGlossary
+	for _, glossary := range glossaries {
+		if glossary.Name == name && strings.EqualFold(glossary.SourceLang, d.source) && strings.EqualFold(glossary.TargetLang, d.target) {
+			existing = append(existing, glossary)
+		}
+	}
+
+	if len(existing) == 1 {
+		current, err := d.GlossaryEntries(ctx, existing[0].ID)
+		if err != nil {
+			return nil, false, err
+		}
+		if sameEntries(current, entries) {
+			return &existing[0], false, nil
+		}
+	}
+
+	glossary, err := d.CreateGlossary(ctx, name, entries)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, old := range existing {
+		if err := d.DeleteGlossary(ctx, old.ID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return glossary, true, nil
+}
+
+// Reads glossary entries from a .tsv or .csv file with a source and a target term per line.
+func LoadGlossaryFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv":
+		return readGlossaryEntries(file, '\t')
+	case ".csv":
+		return readGlossaryEntries(file, ',')
+	default:
+		return nil, fmt.Errorf("unsupported glossary file %s, expected .tsv or .csv", path)
+	}
+}
+
+func readGlossaryEntries(r io.Reader, separator rune) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = separator
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	if separator == '\t' {
+		reader.LazyQuotes = true
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]string, len(records))
+	for _, record := range records {
+		source, target := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if source == "" || target == "" {
+			continue
+		}
+		entries[source] = target
+	}
+
+	return entries, nil
+}
+
+// Formats the entries the way DeepL expects them, sorted so the output is stable.
+func glossaryTSV(entries map[string]string) (string, error) {
+	if len(entries) == 0 {
+		return "", errors.New("glossary has no entries")
+	}
+
+	sources := make([]string, 0, len(entries))
+	for source := range entries {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var builder strings.Builder
+	for _, source := range sources {
+		target := entries[source]
+		if strings.ContainsAny(source+target, "\t\r\n") {
+			return "", fmt.Errorf("glossary entry %q contains a tab or a line break", source)
+		}
+		builder.WriteString(source + "\t" + target + "\n")
+	}
+
+	return builder.String(), nil
+}
+
+func sameEntries(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for source, target := range a {
+		if b[source] != target {
+			return false
+		}
+	}
+	return true
+}
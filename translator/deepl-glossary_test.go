@@ -0,0 +1,113 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Fake of the DeepL glossaries API keeping the glossaries in memory.
+type fakeGlossaries struct {
+	mu         sync.Mutex
+	glossaries map[string]DeepLGlossary
+	entries    map[string]string
+	created    int
+}
+
+func (f *fakeGlossaries) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "DeepL-Auth-Key key" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/v2/glossaries/")
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/v2/glossaries":
+		f.created++
+		glossary := DeepLGlossary{
+			ID:         fmt.Sprintf("g%d", f.created),
+			Name:       r.FormValue("name"),
+			Ready:      true,
+			SourceLang: r.FormValue("source_lang"),
+			TargetLang: r.FormValue("target_lang"),
+			EntryCount: strings.Count(r.FormValue("entries"), "\n"),
+		}
+		f.glossaries[glossary.ID] = glossary
+		f.entries[glossary.ID] = r.FormValue("entries")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(glossary)
+	case r.Method == "GET" && r.URL.Path == "/v2/glossaries":
+		var glossaries []DeepLGlossary
+		for _, glossary := range f.glossaries {
+			glossaries = append(glossaries, glossary)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"glossaries": glossaries})
+	case r.Method == "GET" && strings.HasSuffix(id, "/entries"):
+		w.Write([]byte(f.entries[strings.TrimSuffix(id, "/entries")]))
+	case r.Method == "DELETE":
+		delete(f.glossaries, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestDeepLSyncGlossary(t *testing.T) {
+	fake := &fakeGlossaries{glossaries: map[string]DeepLGlossary{}, entries: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	d := NewDeepLTranslator(true, "key", "en", "de", nil)
+	d.apiURL = server.URL + "/v2/"
+
+	path := filepath.Join(t.TempDir(), "terms.csv")
+	os.WriteFile(path, []byte("widget,Widget\n\"sign in\",anmelden\n"), 0644)
+
+	glossary, updated, err := d.SyncGlossary(context.Background(), "terms", path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !updated || glossary.EntryCount != 2 {
+		t.Fatalf("expected a new glossary with 2 entries, got %+v", glossary)
+	}
+
+	_, updated, err = d.SyncGlossary(context.Background(), "terms", path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated {
+		t.Fatalf("expected no update for an unchanged file")
+	}
+
+	os.WriteFile(path, []byte("widget,Bauteil\nsign in,anmelden\n"), 0644)
+	glossary, updated, err = d.SyncGlossary(context.Background(), "terms", path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !updated || glossary.ID != "g2" {
+		t.Fatalf("expected the glossary to be created again, got %+v", glossary)
+	}
+
+	glossaries, _ := d.ListGlossaries(context.Background())
+	if len(glossaries) != 1 || glossaries[0].ID != "g2" {
+		t.Fatalf("expected the previous glossary to be deleted, got %+v", glossaries)
+	}
+
+	entries, err := d.GlossaryEntries(context.Background(), "g2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if entries["widget"] != "Bauteil" {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+}
@@ -0,0 +1,88 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// A language as listed by the DeepL languages endpoint.
+// SupportsFormality is only reported for target languages.
+type DeepLLanguage struct {
+	Code              string `json:"language"`
+	Name              string `json:"name"`
+	SupportsFormality bool   `json:"supports_formality"`
+}
+
+// Returns the source or target languages supported by DeepL, languageType being "source" or
+// "target". They are fetched on the first call and cached afterwards, see LoadLanguages.
+func (d *DeepLTranslator) Languages(ctx context.Context, languageType string) ([]DeepLLanguage, error) {
+	if languageType != "source" && languageType != "target" {
+		return nil, fmt.Errorf("invalid language type %q, expected source or target", languageType)
+	}
+
+	d.languagesMu.RLock()
+	languages := d.languages[languageType]
+	d.languagesMu.RUnlock()
+	if languages != nil {
+		return languages, nil
+	}
+
+	if err := d.LoadLanguages(ctx); err != nil {
+		return nil, err
+	}
+
+	d.languagesMu.RLock()
+	defer d.languagesMu.RUnlock()
+	return d.languages[languageType], nil
+}
+
+// Fetches the source and target languages from DeepL and caches them, replacing the static
+// table used by IsLanguageSupported and the formality checks. Call it at startup or whenever
+// the cache should be refreshed, the static table stays in use until it succeeds.
+func (d *DeepLTranslator) LoadLanguages(ctx context.Context) error {
+	languages := make(map[string][]DeepLLanguage, 2)
+	for _, languageType := range []string{"source", "target"} {
+		params := url.Values{}
+		params.Set("type", languageType)
+
+		var response []DeepLLanguage
+		if err := d.doJSON(ctx, "GET", "languages", params, &response); err != nil {
+			return err
+		}
+		languages[languageType] = response
+	}
+
+	supportedLanguages := make(map[string]string)
+	for _, languageType := range []string{"source", "target"} {
+		for _, language := range languages[languageType] {
+			supportedLanguages[strings.ToLower(language.Name)] = language.Code
+		}
+	}
+
+	d.languagesMu.Lock()
+	defer d.languagesMu.Unlock()
+	d.languages = languages
+	d.supportedLanguages = supportedLanguages
+
+	return nil
+}
+
+// Reports whether the target language accepts the more and less formalities, using the
+// languages fetched from DeepL when they are loaded.
+func (d *DeepLTranslator) SupportsFormality(target string) bool {
+	d.languagesMu.RLock()
+	defer d.languagesMu.RUnlock()
+
+	if targets, ok := d.languages["target"]; ok {
+		for _, language := range targets {
+			if strings.EqualFold(language.Code, target) {
+				return language.SupportsFormality
+			}
+		}
+		return false
+	}
+
+	return deeplFormalityLanguages[strings.ToUpper(target)]
+}
@@ -0,0 +1,58 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeepLLoadLanguages(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("type") {
+		case "source":
+			w.Write([]byte(`[{"language":"EN","name":"English"},{"language":"AR","name":"Arabic"}]`))
+		case "target":
+			w.Write([]byte(`[{"language":"DE","name":"German","supports_formality":true},` +
+				`{"language":"AR","name":"Arabic","supports_formality":false},` +
+				`{"language":"EN-US","name":"English (American)","supports_formality":false}]`))
+		}
+	}))
+	defer server.Close()
+
+	d := NewDeepLTranslator(true, "key", "EN", "AR", nil)
+	d.apiURL = server.URL + "/"
+
+	if d.IsLanguageSupported("arabic") {
+		t.Fatalf("expected the static table to be used before loading")
+	}
+
+	targets, err := d.Languages(context.Background(), "target")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(targets) != 3 || !targets[0].SupportsFormality {
+		t.Fatalf("unexpected target languages %+v", targets)
+	}
+
+	if _, err := d.Languages(context.Background(), "source"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected the languages to be cached, got %d requests", requests)
+	}
+
+	if !d.IsLanguageSupported("arabic") || !d.IsLanguageSupported("EN-US") {
+		t.Fatalf("expected the fetched languages to be supported")
+	}
+
+	if !d.SupportsFormality("de") || d.SupportsFormality("AR") || d.SupportsFormality("FR") {
+		t.Fatalf("expected formality support to follow the fetched languages")
+	}
+
+	if _, err := d.TranslateWithOptions("hello", DeepLOptions{Formality: DeepLFormalityLess}); err == nil {
+		t.Fatalf("expected formality to be refused for Arabic, got nil")
+	}
+}
@@ -0,0 +1,147 @@
+package translator
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Whether the translation should lean towards formal or informal language.
+type DeepLFormality string
+
+const (
+	DeepLFormalityDefault DeepLFormality = "default"
+	// Only supported by some target languages, see DeepLTranslator.SupportsFormality.
+	DeepLFormalityMore DeepLFormality = "more"
+	// Only supported by some target languages, see DeepLTranslator.SupportsFormality.
+	DeepLFormalityLess DeepLFormality = "less"
+	// Uses more formal language when the target language supports it.
+	DeepLFormalityPreferMore DeepLFormality = "prefer_more"
+	// Uses less formal language when the target language supports it.
+	DeepLFormalityPreferLess DeepLFormality = "prefer_less"
+)
+
+// Which kind of markup the text contains.
+type DeepLTagHandling string
+
+const (
+	DeepLTagHandlingXML  DeepLTagHandling = "xml"
+	DeepLTagHandlingHTML DeepLTagHandling = "html"
+)
+
+// How the text is split into sentences before being translated.
+type DeepLSplitSentences string
+
+const (
+	// Translates the whole text as a single sentence.
+	DeepLSplitNone DeepLSplitSentences = "0"
+	// Splits on punctuation and on newlines.
+	DeepLSplitAll DeepLSplitSentences = "1"
+	// Splits on punctuation only.
+	DeepLSplitNoNewlines DeepLSplitSentences = "nonewlines"
+)
+
+// Which DeepL model translates the text.
+type DeepLModelType string
+
+const (
+	DeepLModelQualityOptimized       DeepLModelType = "quality_optimized"
+	DeepLModelLatencyOptimized       DeepLModelType = "latency_optimized"
+	DeepLModelPreferQualityOptimized DeepLModelType = "prefer_quality_optimized"
+)
+
+// Optional parameters of a DeepL translation, the zero value leaves every one to DeepL's default.
+type DeepLOptions struct {
+	Formality          DeepLFormality
+	TagHandling        DeepLTagHandling
+	IgnoreTags         []string
+	NonSplittingTags   []string
+	SplitSentences     DeepLSplitSentences
+	PreserveFormatting bool
+	// Additional text that helps the translation but is not translated itself.
+	Context    string
+	ModelType  DeepLModelType
+	GlossaryID string
+}
+
+// Target languages accepting the more and less formalities, used until the languages are
+// fetched from DeepL.
+var deeplFormalityLanguages = map[string]bool{
+	"DE": true, "FR": true, "IT": true, "ES": true, "ES-419": true, "NL": true,
+	"PL": true, "PT-BR": true, "PT-PT": true, "JA": true, "RU": true,
+}
+
+// Checks the options against each other and against the target language.
+func (d *DeepLTranslator) validateOptions(opts DeepLOptions) error {
+	switch opts.Formality {
+	case "", DeepLFormalityDefault, DeepLFormalityPreferMore, DeepLFormalityPreferLess:
+	case DeepLFormalityMore, DeepLFormalityLess:
+		if !d.SupportsFormality(d.target) {
+			return fmt.Errorf("formality %q is not available for target language %s: %w", opts.Formality, d.target, errs.ErrLanguageNotSupported)
+		}
+	default:
+		return fmt.Errorf("invalid formality %q", opts.Formality)
+	}
+
+	switch opts.TagHandling {
+	case "":
+		if len(opts.IgnoreTags) > 0 || len(opts.NonSplittingTags) > 0 {
+			return fmt.Errorf("ignore and non-splitting tags require tag handling")
+		}
+	case DeepLTagHandlingXML, DeepLTagHandlingHTML:
+	default:
+		return fmt.Errorf("invalid tag handling %q", opts.TagHandling)
+	}
+
+	switch opts.SplitSentences {
+	case "", DeepLSplitNone, DeepLSplitAll, DeepLSplitNoNewlines:
+	default:
+		return fmt.Errorf("invalid sentence splitting %q", opts.SplitSentences)
+	}
+
+	switch opts.ModelType {
+	case "", DeepLModelQualityOptimized, DeepLModelLatencyOptimized, DeepLModelPreferQualityOptimized:
+	default:
+		return fmt.Errorf("invalid model type %q", opts.ModelType)
+	}
+
+	// DeepL rejects a glossary when it has to detect the source language
+	if opts.GlossaryID != "" && (d.source == "" || d.source == "auto") {
+		return fmt.Errorf("glossaries require an explicit source language")
+	}
+
+	return nil
+}
+
+// Adds the options that are set to the request parameters.
+func (opts DeepLOptions) apply(params url.Values) {
+	if opts.Formality != "" {
+		params.Set("formality", string(opts.Formality))
+	}
+	if opts.TagHandling != "" {
+		params.Set("tag_handling", string(opts.TagHandling))
+	}
+	if len(opts.IgnoreTags) > 0 {
+		params.Set("ignore_tags", strings.Join(opts.IgnoreTags, ","))
+	}
+	if len(opts.NonSplittingTags) > 0 {
+		params.Set("non_splitting_tags", strings.Join(opts.NonSplittingTags, ","))
+	}
+	if opts.SplitSentences != "" {
+		params.Set("split_sentences", string(opts.SplitSentences))
+	}
+	if opts.PreserveFormatting {
+		params.Set("preserve_formatting", "1")
+	}
+	if opts.Context != "" {
+		params.Set("context", opts.Context)
+	}
+	if opts.ModelType != "" {
+		params.Set("model_type", string(opts.ModelType))
+	}
+	if opts.GlossaryID != "" {
+		params.Set("glossary_id", opts.GlossaryID)
+	}
+}
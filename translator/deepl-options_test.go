@@ -0,0 +1,89 @@
+package translator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+func TestDeepLOptionsValidation(t *testing.T) {
+	tests := []struct {
+		target string
+		opts   DeepLOptions
+		valid  bool
+	}{
+		{"DE", DeepLOptions{Formality: DeepLFormalityLess}, true},
+		{"EN-US", DeepLOptions{Formality: DeepLFormalityLess}, false},
+		{"EN-US", DeepLOptions{Formality: DeepLFormalityPreferLess}, true},
+		{"DE", DeepLOptions{Formality: "casual"}, false},
+		{"DE", DeepLOptions{IgnoreTags: []string{"x"}}, false},
+		{"DE", DeepLOptions{TagHandling: DeepLTagHandlingXML, IgnoreTags: []string{"x"}}, true},
+		{"DE", DeepLOptions{SplitSentences: "2"}, false},
+		{"DE", DeepLOptions{ModelType: DeepLModelLatencyOptimized}, true},
+	}
+
+	for _, test := range tests {
+		d := NewDeepLTranslator(true, "key", "EN", test.target, nil)
+		err := d.validateOptions(test.opts)
+		if test.valid && err != nil {
+			t.Errorf("%s %+v: expected no error, got %v", test.target, test.opts, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s %+v: expected an error, got nil", test.target, test.opts)
+		}
+	}
+
+	d := NewDeepLTranslator(true, "key", "EN", "EN-GB", nil)
+	if _, err := d.TranslateWithOptions("hello", DeepLOptions{Formality: DeepLFormalityMore}); !errors.Is(err, errs.ErrLanguageNotSupported) {
+		t.Fatalf("expected ErrLanguageNotSupported, got %v", err)
+	}
+	for _, source := range []string{"", "auto"} {
+		d := NewDeepLTranslator(true, "key", source, "DE", nil)
+		if err := d.validateOptions(DeepLOptions{GlossaryID: "g1"}); err == nil {
+			t.Fatalf("source %q: expected an error for a glossary without a source language, got nil", source)
+		}
+		if err := d.validateOptions(DeepLOptions{}); err != nil {
+			t.Fatalf("source %q: expected no error without a glossary, got %v", source, err)
+		}
+	}
+}
+
+func TestDeepLOptionsAreSent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := map[string]string{
+			"formality":           "prefer_more",
+			"tag_handling":        "html",
+			"ignore_tags":         "code,pre",
+			"split_sentences":     "nonewlines",
+			"preserve_formatting": "1",
+			"context":             "A settings screen",
+			"glossary_id":         "g1",
+		}
+		for key, value := range expected {
+			if r.FormValue(key) != value {
+				t.Errorf("expected %s=%s, got %q", key, value, r.FormValue(key))
+			}
+		}
+		w.Write([]byte(`{"translations":[{"detected_source_language":"EN","text":"Einstellungen"}]}`))
+	}))
+	defer server.Close()
+
+	d := NewDeepLTranslator(true, "key", "EN", "DE", nil)
+	d.apiURL = server.URL + "/"
+
+	opts := DeepLOptions{
+		Formality:          DeepLFormalityPreferMore,
+		TagHandling:        DeepLTagHandlingHTML,
+		IgnoreTags:         []string{"code", "pre"},
+		SplitSentences:     DeepLSplitNoNewlines,
+		PreserveFormatting: true,
+		Context:            "A settings screen",
+		GlossaryID:         "g1",
+	}
+	if _, err := d.TranslateBatchWithOptions([]string{"Settings"}, opts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
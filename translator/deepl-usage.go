@@ -0,0 +1,101 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// How long the quota guard relies on the usage it fetched before asking DeepL again.
+const deeplUsageTTL = time.Minute
+
+// Characters translated in the current billing period and the limit of the account.
+type DeepLUsage struct {
+	CharacterCount int64 `json:"character_count"`
+	CharacterLimit int64 `json:"character_limit"`
+}
+
+// Returns how many characters can still be translated, or -1 when the account has no limit.
+func (u DeepLUsage) Remaining() int64 {
+	if u.CharacterLimit <= 0 {
+		return -1
+	}
+	return max(u.CharacterLimit-u.CharacterCount, 0)
+}
+
+// Fetches the character usage of the account for the current billing period.
+func (d *DeepLTranslator) Usage(ctx context.Context) (*DeepLUsage, error) {
+	var usage DeepLUsage
+	if err := d.doJSON(ctx, "GET", "usage", nil, &usage); err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// Checks the remaining quota before every translation. Texts that would exceed it are sent to
+// the fallback translator, or refused with errs.ErrQuotaExceeded when the fallback is nil.
+// The usage is fetched at most once a minute and counted down locally in between.
+func (d *DeepLTranslator) EnableQuotaGuard(fallback BatchTranslator) {
+	d.quotaGuard = true
+	d.quotaFallback = fallback
+}
+
+// Stops checking the remaining quota before translating.
+func (d *DeepLTranslator) DisableQuotaGuard() {
+	d.quotaGuard = false
+	d.quotaFallback = nil
+
+	d.usageMu.Lock()
+	d.usage = nil
+	d.usageMu.Unlock()
+}
+
+// Reports whether the texts fit in the remaining quota, DeepL bills every character.
+// The characters of fitting texts are added to the cached usage right away.
+func (d *DeepLTranslator) fitsQuota(ctx context.Context, texts []string) (bool, error) {
+	d.usageMu.Lock()
+	defer d.usageMu.Unlock()
+
+	if d.usage == nil || time.Since(d.usageFetched) > deeplUsageTTL {
+		usage, err := d.Usage(ctx)
+		if err != nil {
+			return false, err
+		}
+		d.usage, d.usageFetched = usage, time.Now()
+	}
+
+	var estimated int64
+	for _, text := range texts {
+		estimated += int64(utf8.RuneCountInString(text))
+	}
+
+	if remaining := d.usage.Remaining(); remaining >= 0 && estimated > remaining {
+		return false, nil
+	}
+
+	d.usage.CharacterCount += estimated
+	return true, nil
+}
+
+// Sends the texts to the fallback translator when there is one.
+func (d *DeepLTranslator) reroute(texts []string) ([]DeepLTranslation, error) {
+	if d.quotaFallback == nil {
+		return nil, fmt.Errorf("batch exceeds the remaining DeepL characters: %w", errs.ErrQuotaExceeded)
+	}
+
+	translated, err := d.quotaFallback.TranslateBatch(texts)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]DeepLTranslation, len(translated))
+	for i, text := range translated {
+		translations[i] = DeepLTranslation{Text: text}
+	}
+
+	return translations, nil
+}
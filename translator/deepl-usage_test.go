@@ -0,0 +1,94 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Translators that can serve as the fallback of the quota guard.
+var (
+	_ BatchTranslator = (*GoogleTranslator)(nil)
+	_ BatchTranslator = (*GoogleCloudTranslator)(nil)
+	_ BatchTranslator = (*DeepLTranslator)(nil)
+	_ BatchTranslator = (*AzureTranslator)(nil)
+	_ BatchTranslator = (*LibreTranslator)(nil)
+	_ BatchTranslator = (*MyMemoryTranslator)(nil)
+	_ BatchTranslator = (*ApertiumTranslator)(nil)
+	_ BatchTranslator = (*ApertiumCommand)(nil)
+	_ BatchTranslator = (*PapagoTranslator)(nil)
+)
+
+type upperCaseTranslator struct{}
+
+func (upperCaseTranslator) TranslateBatch(texts []string) ([]string, error) {
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = strings.ToUpper(text)
+	}
+	return translated, nil
+}
+
+func TestDeepLQuotaGuard(t *testing.T) {
+	var translateRequests, usageRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/usage":
+			usageRequests++
+			w.Write([]byte(`{"character_count":499990,"character_limit":500000}`))
+		case "/v2/translate":
+			translateRequests++
+			w.Write([]byte(`{"translations":[{"text":"hallo"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	d := NewDeepLTranslator(true, "key", "EN", "DE", nil)
+	d.apiURL = server.URL + "/v2/"
+
+	usage, err := d.Usage(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if usage.Remaining() != 10 {
+		t.Fatalf("expected 10 remaining characters, got %d", usage.Remaining())
+	}
+
+	d.EnableQuotaGuard(nil)
+	if _, err := d.Translate("hello"); err != nil {
+		t.Fatalf("expected a text within the quota to be translated, got %v", err)
+	}
+
+	_, err = d.TranslateBatch([]string{"hello", "world", "again"})
+	if !errors.Is(err, errs.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	d.EnableQuotaGuard(upperCaseTranslator{})
+	translated, err := d.TranslateBatch([]string{"hello", "world", "again"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if translated[2] != "AGAIN" {
+		t.Fatalf("expected the batch to be rerouted, got %q", translated)
+	}
+
+	if translateRequests != 1 {
+		t.Fatalf("expected only the text within the quota to reach DeepL, got %d requests", translateRequests)
+	}
+
+	// the guard fetched the usage once, on top of the explicit Usage call
+	if usageRequests != 2 {
+		t.Fatalf("expected 2 usage requests, got %d", usageRequests)
+	}
+
+	// the 5 characters translated above are counted locally
+	if _, err := d.TranslateBatch([]string{"hello", "x"}); err != nil || translateRequests != 1 {
+		t.Fatalf("expected the batch over the remaining 5 characters to be rerouted, got %v", err)
+	}
+}
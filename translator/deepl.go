@@ -1,19 +1,29 @@
 package translator
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
-	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/kashari/go-translate/constants"
 )
 
+// Limits of a single translate request.
+const (
+	deeplMaxTexts       = 50
+	deeplMaxRequestSize = 128 * 1024
+)
+
 type DeepLTranslator struct {
-	baseURL            string
+	apiURL             string
 	source             string
 	target             string
 	proxies            *url.URL
@@ -21,6 +31,21 @@ type DeepLTranslator struct {
 	supportedLanguages map[string]string
 	client             *http.Client
 	apiKey             string
+	options            DeepLOptions
+	pollInterval       time.Duration
+	quotaGuard         bool
+	quotaFallback      BatchTranslator
+	usageMu            sync.Mutex
+	usage              *DeepLUsage
+	usageFetched       time.Time
+	languagesMu        sync.RWMutex
+	languages          map[string][]DeepLLanguage
+}
+
+// A translated text with the language DeepL detected in the source text.
+type DeepLTranslation struct {
+	Text                   string `json:"text"`
+	DetectedSourceLanguage string `json:"detected_source_language"`
 }
 
 // Creates a new instance of DeepLTranslator.
@@ -29,118 +54,224 @@ func NewDeepLTranslator(freeApi bool, apiKey string, source, target string, prox
 	if apiKey == "" {
 		log.Panic("API key is required for the paid API")
 	}
-	var baseURL string
+	var apiURL string
 	var urlParams url.Values = url.Values{}
 
 	if freeApi {
-		baseURL = fmt.Sprintf("%s%s", constants.BASE_URLS["DEEPL_FREE"], "translate")
+		apiURL = constants.BASE_URLS["DEEPL_FREE"]
 	} else {
-		baseURL = fmt.Sprintf("%s%s", constants.BASE_URLS["DEEPL"], "translate")
+		apiURL = constants.BASE_URLS["DEEPL"]
 	}
 
 	urlParams.Add("source_lang", source)
 	urlParams.Add("target_lang", target)
 
 	return &DeepLTranslator{
-		baseURL:            baseURL,
+		apiURL:             apiURL,
 		source:             source,
 		target:             target,
 		proxies:            proxies,
 		urlParams:          urlParams,
 		supportedLanguages: constants.DEEPL_LANGUAGE_TO_CODE,
-		client:             &http.Client{},
+		client:             newHTTPClient(proxies),
 		apiKey:             apiKey,
+		pollInterval:       time.Second,
 	}
 }
 
+// Sets the glossary used by the following translations, an empty ID disables it.
+// The glossary languages must match the source and target languages of the translator.
+func (d *DeepLTranslator) SetGlossary(glossaryID string) {
+	d.options.GlossaryID = glossaryID
+}
+
+// Sets the options used by Translate, TranslateBatch and TranslateFile.
+func (d *DeepLTranslator) SetOptions(opts DeepLOptions) {
+	d.options = opts
+}
+
 func (d *DeepLTranslator) Translate(text string) (string, error) {
-	d.urlParams.Set("text", text)
-	// send a request with all the params
-	req, err := http.NewRequest("POST", d.baseURL, nil)
+	return d.TranslateWithOptions(text, d.options)
+}
+
+// Translates the text with the given options instead of the ones of the translator.
+func (d *DeepLTranslator) TranslateWithOptions(text string, opts DeepLOptions) (string, error) {
+	translations, err := d.TranslateDetailed(context.Background(), []string{text}, opts)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", d.apiKey))
 
-	req.URL.RawQuery = d.urlParams.Encode()
+	return translations[0].Text, nil
+}
 
-	resp, err := d.client.Do(req)
+func (d *DeepLTranslator) TranslateBatch(texts []string) ([]string, error) {
+	return d.TranslateBatchWithOptions(texts, d.options)
+}
+
+// Translates the texts with the given options instead of the ones of the translator.
+func (d *DeepLTranslator) TranslateBatchWithOptions(texts []string, opts DeepLOptions) ([]string, error) {
+	translations, err := d.TranslateDetailed(context.Background(), texts, opts)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	defer resp.Body.Close()
+	translated := make([]string, len(translations))
+	for i, translation := range translations {
+		translated[i] = translation.Text
+	}
+
+	return translated, nil
+}
+
+// Translates the texts, sending up to 50 of them per request as long as the request stays
+// under DeepL's size limit, and returns the translations in the same order. With the quota
+// guard enabled, texts exceeding the remaining quota are rerouted or refused up front.
+func (d *DeepLTranslator) TranslateDetailed(ctx context.Context, texts []string, opts DeepLOptions) ([]DeepLTranslation, error) {
+	if err := d.validateOptions(opts); err != nil {
+		return nil, err
+	}
+
+	if d.quotaGuard {
+		fits, err := d.fitsQuota(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		if !fits {
+			return d.reroute(texts)
+		}
+	}
 
-	// read the response
-	translatedText, err := readResponse(resp)
+	params := url.Values{}
+	for k, v := range d.urlParams {
+		params[k] = v
+	}
+	if d.source == "" || d.source == "auto" {
+		params.Del("source_lang")
+	}
+	opts.apply(params)
+	// the texts share the room left by the other parameters
+	textSize := func(text string) int {
+		return len("&text=") + len(url.QueryEscape(text))
+	}
+	chunks, err := chunkTexts(texts, deeplMaxTexts, deeplMaxRequestSize-len(params.Encode()), textSize)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	translations := make([]DeepLTranslation, 0, len(texts))
+	for _, chunk := range chunks {
+		translated, err := d.translateTexts(ctx, params, chunk)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, translated...)
 	}
 
-	return translatedText, nil
+	return translations, nil
 }
 
-func readResponse(resp *http.Response) (string, error) {
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to translate text: %s", resp.Status)
+// Translates the texts with a single request.
+func (d *DeepLTranslator) translateTexts(ctx context.Context, params url.Values, texts []string) ([]DeepLTranslation, error) {
+	form := url.Values{}
+	for k, v := range params {
+		form[k] = v
+	}
+	form["text"] = texts
+
+	var response struct {
+		Translations []DeepLTranslation `json:"translations"`
+	}
+	if err := d.doJSON(ctx, "POST", "translate", form, &response); err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	if len(response.Translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(response.Translations))
+	}
+
+	return response.Translations, nil
+}
+
+// Sends an authenticated request to the DeepL API and decodes the JSON response into out.
+func (d *DeepLTranslator) doJSON(ctx context.Context, method, path string, form url.Values, out interface{}) error {
+	resp, err := d.request(ctx, method, path, form, "application/json")
 	if err != nil {
-		return "", err
+		return err
 	}
+	defer resp.Body.Close()
 
-	return string(body), nil
+	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func (d *DeepLTranslator) TranslateBatch(texts []string) ([]string, error) {
-	var wg sync.WaitGroup
-	translations := make([]string, len(texts))
-	ch := make(chan struct {
-		index int
-		text  string
-		err   error
-	}, len(texts))
-
-	for i, text := range texts {
-		wg.Add(1)
-		go func(i int, text string) {
-			defer wg.Done()
-			translated, err := d.Translate(text)
-			ch <- struct {
-				index int
-				text  string
-				err   error
-			}{i, translated, err}
-		}(i, text)
-	}
-
-	go func() {
-		wg.Wait()
-		close(ch)
-	}()
-
-	for result := range ch {
-		if result.err != nil {
-			return nil, result.err
+// Sends an authenticated request to the DeepL API, returning an error for unsuccessful statuses.
+// The form is sent as the body of POST requests and as the query of the other ones.
+func (d *DeepLTranslator) request(ctx context.Context, method, path string, form url.Values, accept string) (*http.Response, error) {
+	var body io.Reader
+	if method == "POST" && form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, d.apiURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else if form != nil {
+		req.URL.RawQuery = form.Encode()
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	return d.send(req)
+}
+
+// Authenticates and sends the request, returning an error for unsuccessful statuses.
+func (d *DeepLTranslator) send(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", d.apiKey))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		var response struct {
+			Message string `json:"message"`
 		}
-		translations[result.index] = result.text
+		json.NewDecoder(resp.Body).Decode(&response)
+		return nil, newStatusError(resp, response.Message)
 	}
 
-	return translations, nil
+	return resp, nil
 }
 
+// Translates a file, the document types going through TranslateDocument and their translated
+// content being returned as is. Any other file must hold UTF-8 text.
 func (d *DeepLTranslator) TranslateFile(path string) (string, error) {
-	text, err := os.ReadFile(path)
+	if deeplDocumentTypes[strings.ToLower(filepath.Ext(path))] {
+		var translated strings.Builder
+		if _, err := d.TranslateDocument(context.Background(), path, &translated); err != nil {
+			return "", err
+		}
+		return translated.String(), nil
+	}
+
+	text, err := readTextFile(path)
 	if err != nil {
 		return "", err
 	}
 
-	return d.Translate(string(text))
+	return d.Translate(text)
 }
 
 func (d *DeepLTranslator) MapLanguageToCode(languages ...string) (string, string) {
+	d.languagesMu.RLock()
+	defer d.languagesMu.RUnlock()
+
 	var mappedLanguages []string
 	for _, language := range languages {
 		if language == "auto" || contains(d.supportedLanguages, language) {
@@ -159,9 +290,13 @@ func (d *DeepLTranslator) SameSourceTarget() bool {
 }
 
 func (d *DeepLTranslator) GetSupportedLanguages() interface{} {
+	d.languagesMu.RLock()
+	defer d.languagesMu.RUnlock()
 	return d.supportedLanguages
 }
 
 func (d *DeepLTranslator) IsLanguageSupported(language string) bool {
+	d.languagesMu.RLock()
+	defer d.languagesMu.RUnlock()
 	return language == "auto" || contains(d.supportedLanguages, language) || d.supportedLanguages[language] != ""
 }
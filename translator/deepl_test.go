@@ -0,0 +1,116 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// Starts a fake DeepL translate endpoint upper-casing every text parameter.
+func newFakeDeepLTranslate(tb testing.TB, requests *int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(requests, 1)
+		r.ParseForm()
+
+		var translations []DeepLTranslation
+		for _, text := range r.PostForm["text"] {
+			translations = append(translations, DeepLTranslation{Text: strings.ToUpper(text), DetectedSourceLanguage: "EN"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"translations": translations})
+	}))
+	tb.Cleanup(server.Close)
+	return server
+}
+
+func TestDeepLTranslateParsesResponse(t *testing.T) {
+	var requests int64
+	d := NewDeepLTranslator(true, "key", "auto", "DE", nil)
+	d.apiURL = newFakeDeepLTranslate(t, &requests).URL + "/"
+
+	translated, err := d.Translate("hello")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if translated != "HELLO" {
+		t.Fatalf("expected 'HELLO', got %s", translated)
+	}
+
+	translations, err := d.TranslateDetailed(context.Background(), []string{"hello"}, DeepLOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if translations[0].DetectedSourceLanguage != "EN" {
+		t.Fatalf("expected detected language 'EN', got %s", translations[0].DetectedSourceLanguage)
+	}
+}
+
+func TestDeepLTranslateBatchUsesMultiTextRequests(t *testing.T) {
+	var requests int64
+	d := NewDeepLTranslator(true, "key", "EN", "DE", nil)
+	d.apiURL = newFakeDeepLTranslate(t, &requests).URL + "/"
+
+	texts := make([]string, 120)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+
+	translations, err := d.TranslateBatch(texts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i, translation := range translations {
+		if translation != fmt.Sprintf("TEXT %d", i) {
+			t.Fatalf("expected 'TEXT %d', got %s", i, translation)
+		}
+	}
+
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestDeepLTranslateBatchRespectsRequestSize(t *testing.T) {
+	var requests int64
+	d := NewDeepLTranslator(true, "key", "EN", "DE", nil)
+	d.apiURL = newFakeDeepLTranslate(t, &requests).URL + "/"
+
+	texts := []string{strings.Repeat("a", 100*1024), strings.Repeat("b", 100*1024)}
+	if _, err := d.TranslateBatch(texts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+
+	if _, err := d.Translate(strings.Repeat("c", 200*1024)); err == nil {
+		t.Fatalf("expected an error for a text over the request size, got nil")
+	}
+}
+
+func TestDeepLUsesProxy(t *testing.T) {
+	var requests int64
+	// the fake endpoint acts as the proxy, receiving the request for the real host
+	proxy := newFakeDeepLTranslate(t, &requests)
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	d := NewDeepLTranslator(true, "key", "EN", "DE", proxyURL)
+	d.apiURL = "http://deepl.invalid/v2/"
+
+	translated, err := d.Translate("hello")
+	if err != nil || translated != "HELLO" {
+		t.Fatalf("expected 'HELLO' through the proxy, got %q %v", translated, err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request through the proxy, got %d", requests)
+	}
+}
@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"context"
+	"strings"
+)
+
+// A headword of a bilingual dictionary together with its translations.
+type DictionaryEntry struct {
+	Lemma        string
+	PartOfSpeech string
+	Gender       string
+	// Whether the dictionary highlights the entry as one of the most relevant ones.
+	Featured bool
+	// How often the entry is used as noted by the dictionary, such as "rarely used", empty when not noted.
+	Frequency    string
+	Translations []DictionaryTranslation
+	Audio        []AudioLink
+}
+
+// A translation of a dictionary entry.
+type DictionaryTranslation struct {
+	Text         string
+	PartOfSpeech string
+	Gender       string
+	Featured     bool
+	// The meaning of the entry the translation belongs to, when the dictionary groups them by sense.
+	Sense string
+	// How common the translation is, such as "often used" or "less common", empty when not noted.
+	UsageFrequency string
+	Examples       []ExamplePair
+	Audio          []AudioLink
+}
+
+// An example sentence and its translation.
+type ExamplePair struct {
+	Source string
+	Target string
+}
+
+// A pronunciation recording.
+type AudioLink struct {
+	URL      string
+	Language string
+}
+
+// Implemented by the dictionaries that look words up into structured entries.
+type Dictionary interface {
+	Lookup(ctx context.Context, word string) ([]DictionaryEntry, error)
+}
+
+// Splits a grammatical description such as "noun, neuter" into the part of speech and the gender.
+func splitPartOfSpeech(description string) (string, string) {
+	partOfSpeech, rest, _ := strings.Cut(description, ",")
+	for _, gender := range []string{"masculine", "feminine", "neuter"} {
+		if strings.Contains(rest, gender) {
+			return strings.TrimSpace(partOfSpeech), gender
+		}
+	}
+	return strings.TrimSpace(partOfSpeech), ""
+}
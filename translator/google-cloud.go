@@ -0,0 +1,382 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kashari/go-translate/constants"
+)
+
+// Limits of a single Cloud Translation request, shared by v2 and v3.
+const (
+	googleCloudMaxTexts = 128
+	googleCloudMaxChars = 30000
+)
+
+// Represents a translator using the official Google Cloud Translation API.
+// It uses the v2 (Basic) API unless a project is set, in which case it uses v3 (Advanced).
+type GoogleCloudTranslator struct {
+	baseURL            string
+	source             string
+	target             string
+	proxies            *url.URL
+	client             *http.Client
+	apiKey             string
+	tokenSource        func(ctx context.Context) (string, error)
+	project            string
+	location           string
+	glossary           string
+	format             string
+	languagesMu        sync.RWMutex
+	supportedLanguages map[string]string
+}
+
+// A translated text as returned by the Cloud Translation API.
+// GlossaryText is only set on v3 requests using a glossary.
+type GoogleCloudTranslation struct {
+	Text           string
+	DetectedSource string
+	GlossaryText   string
+}
+
+// A language supported by the Cloud Translation API.
+type GoogleCloudLanguage struct {
+	Code          string
+	Name          string
+	SupportSource bool
+	SupportTarget bool
+}
+
+// Creates a new instance of GoogleCloudTranslator.
+// When apiKey is empty the key is read from the GOOGLE_API_KEY environment variable.
+func NewGoogleCloudTranslator(source, target string, proxies *url.URL, apiKey string) *GoogleCloudTranslator {
+	if apiKey == "" {
+		apiKey = os.Getenv(constants.GOOGLE_ENV_VAR)
+	}
+
+	return &GoogleCloudTranslator{
+		baseURL:            constants.BASE_URLS["GOOGLE_CLOUD"],
+		source:             source,
+		target:             target,
+		proxies:            proxies,
+		client:             newHTTPClient(proxies),
+		apiKey:             apiKey,
+		location:           "global",
+		format:             "text",
+		supportedLanguages: constants.GOOGLE_LANGUAGES_TO_CODES,
+	}
+}
+
+// Sets the root URL of the API, mostly useful to point the translator to a stand-in server.
+func (g *GoogleCloudTranslator) SetBaseURL(baseURL string) {
+	g.baseURL = strings.TrimRight(baseURL, "/") + "/"
+}
+
+// Authenticates with a fixed OAuth access token, such as one issued for a service account.
+func (g *GoogleCloudTranslator) SetBearerToken(token string) {
+	g.tokenSource = func(ctx context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+// Authenticates with access tokens returned by the given function, called before every request
+// so it can refresh expired tokens.
+func (g *GoogleCloudTranslator) SetTokenSource(tokenSource func(ctx context.Context) (string, error)) {
+	g.tokenSource = tokenSource
+}
+
+// Switches the translator to the v3 API of the given project.
+// An empty location defaults to global.
+func (g *GoogleCloudTranslator) SetProject(project, location string) {
+	if location == "" {
+		location = "global"
+	}
+	g.project = project
+	g.location = location
+}
+
+// Sets the glossary ID used by v3 requests, the glossary must belong to the project and location.
+func (g *GoogleCloudTranslator) SetGlossary(glossary string) {
+	g.glossary = glossary
+}
+
+// Sets the format of the texts, either "text" or "html".
+func (g *GoogleCloudTranslator) SetFormat(format string) {
+	g.format = format
+}
+
+func (g *GoogleCloudTranslator) Translate(text string) (string, error) {
+	translations, err := g.TranslateDetailed(context.Background(), []string{text})
+	if err != nil {
+		return "", err
+	}
+
+	return translations[0].Text, nil
+}
+
+func (g *GoogleCloudTranslator) TranslateBatch(texts []string) ([]string, error) {
+	translations, err := g.TranslateDetailed(context.Background(), texts)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := make([]string, len(translations))
+	for i, translation := range translations {
+		translated[i] = translation.Text
+	}
+
+	return translated, nil
+}
+
+func (g *GoogleCloudTranslator) TranslateFile(path string) (string, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return g.Translate(string(text))
+}
+
+// Translates the texts, sending as many of them per request as the API allows,
+// and returns the translations in the same order.
+func (g *GoogleCloudTranslator) TranslateDetailed(ctx context.Context, texts []string) ([]GoogleCloudTranslation, error) {
+	chunks, err := chunkTexts(texts, googleCloudMaxTexts, googleCloudMaxChars, utf8.RuneCountInString)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]GoogleCloudTranslation, 0, len(texts))
+	for _, chunk := range chunks {
+		translated, err := g.translateChunk(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, translated...)
+	}
+
+	return translations, nil
+}
+
+func (g *GoogleCloudTranslator) translateChunk(ctx context.Context, texts []string) ([]GoogleCloudTranslation, error) {
+	if g.project != "" {
+		return g.translateV3(ctx, texts)
+	}
+	return g.translateV2(ctx, texts)
+}
+
+func (g *GoogleCloudTranslator) translateV2(ctx context.Context, texts []string) ([]GoogleCloudTranslation, error) {
+	body := map[string]interface{}{
+		"q":      texts,
+		"target": g.target,
+		"format": g.format,
+	}
+	if g.source != "" && g.source != "auto" {
+		body["source"] = g.source
+	}
+
+	var response struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText         string `json:"translatedText"`
+				DetectedSourceLanguage string `json:"detectedSourceLanguage"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+
+	if err := g.do(ctx, "POST", "language/translate/v2", body, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Data.Translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(response.Data.Translations))
+	}
+
+	translations := make([]GoogleCloudTranslation, len(texts))
+	for i, t := range response.Data.Translations {
+		translations[i] = GoogleCloudTranslation{Text: t.TranslatedText, DetectedSource: t.DetectedSourceLanguage}
+	}
+
+	return translations, nil
+}
+
+func (g *GoogleCloudTranslator) translateV3(ctx context.Context, texts []string) ([]GoogleCloudTranslation, error) {
+	body := map[string]interface{}{
+		"contents":           texts,
+		"targetLanguageCode": g.target,
+		"mimeType":           "text/plain",
+	}
+	if g.format == "html" {
+		body["mimeType"] = "text/html"
+	}
+	if g.source != "" && g.source != "auto" {
+		body["sourceLanguageCode"] = g.source
+	}
+	if g.glossary != "" {
+		body["glossaryConfig"] = map[string]string{
+			"glossary": fmt.Sprintf("%s/glossaries/%s", g.parent(), g.glossary),
+		}
+	}
+
+	type translation struct {
+		TranslatedText       string `json:"translatedText"`
+		DetectedLanguageCode string `json:"detectedLanguageCode"`
+	}
+	var response struct {
+		Translations         []translation `json:"translations"`
+		GlossaryTranslations []translation `json:"glossaryTranslations"`
+	}
+
+	if err := g.do(ctx, "POST", "v3/"+g.parent()+":translateText", body, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(response.Translations))
+	}
+
+	translations := make([]GoogleCloudTranslation, len(texts))
+	for i, t := range response.Translations {
+		translations[i] = GoogleCloudTranslation{Text: t.TranslatedText, DetectedSource: t.DetectedLanguageCode}
+		if i < len(response.GlossaryTranslations) {
+			translations[i].GlossaryText = response.GlossaryTranslations[i].TranslatedText
+		}
+	}
+
+	return translations, nil
+}
+
+// Fetches the languages supported by the API, with names in the given display language.
+// The result also replaces the static table used by IsLanguageSupported.
+func (g *GoogleCloudTranslator) Languages(ctx context.Context, displayLanguage string) ([]GoogleCloudLanguage, error) {
+	var languages []GoogleCloudLanguage
+
+	if g.project != "" {
+		var response struct {
+			Languages []struct {
+				LanguageCode  string `json:"languageCode"`
+				DisplayName   string `json:"displayName"`
+				SupportSource bool   `json:"supportSource"`
+				SupportTarget bool   `json:"supportTarget"`
+			} `json:"languages"`
+		}
+
+		path := "v3/" + g.parent() + "/supportedLanguages?displayLanguageCode=" + url.QueryEscape(displayLanguage)
+		if err := g.do(ctx, "GET", path, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, l := range response.Languages {
+			languages = append(languages, GoogleCloudLanguage{Code: l.LanguageCode, Name: l.DisplayName, SupportSource: l.SupportSource, SupportTarget: l.SupportTarget})
+		}
+	} else {
+		var response struct {
+			Data struct {
+				Languages []struct {
+					Language string `json:"language"`
+					Name     string `json:"name"`
+				} `json:"languages"`
+			} `json:"data"`
+		}
+
+		path := "language/translate/v2/languages?target=" + url.QueryEscape(displayLanguage)
+		if err := g.do(ctx, "GET", path, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, l := range response.Data.Languages {
+			languages = append(languages, GoogleCloudLanguage{Code: l.Language, Name: l.Name, SupportSource: true, SupportTarget: true})
+		}
+	}
+
+	supportedLanguages := make(map[string]string, len(languages))
+	for _, l := range languages {
+		name := l.Name
+		if name == "" {
+			name = l.Code
+		}
+		supportedLanguages[strings.ToLower(name)] = l.Code
+	}
+	g.languagesMu.Lock()
+	g.supportedLanguages = supportedLanguages
+	g.languagesMu.Unlock()
+
+	return languages, nil
+}
+
+func (g *GoogleCloudTranslator) parent() string {
+	return fmt.Sprintf("projects/%s/locations/%s", g.project, g.location)
+}
+
+// Sends an authenticated JSON request to the API and decodes the response into out.
+func (g *GoogleCloudTranslator) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if g.tokenSource != nil {
+		token, err := g.tokenSource(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if g.apiKey != "" {
+		req.Header.Set("X-Goog-Api-Key", g.apiKey)
+	} else {
+		return errors.New("an API key or a bearer token is required")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		return newStatusError(resp, response.Error.Message)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (g *GoogleCloudTranslator) SameSourceTarget() bool {
+	return g.source == g.target
+}
+
+func (g *GoogleCloudTranslator) GetSupportedLanguages() interface{} {
+	g.languagesMu.RLock()
+	defer g.languagesMu.RUnlock()
+	return g.supportedLanguages
+}
+
+func (g *GoogleCloudTranslator) IsLanguageSupported(language string) bool {
+	g.languagesMu.RLock()
+	defer g.languagesMu.RUnlock()
+	return language == "auto" || contains(g.supportedLanguages, language) || g.supportedLanguages[language] != ""
+}
@@ -0,0 +1,148 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+func TestGoogleCloudTranslateBatchV2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/language/translate/v2" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-Goog-Api-Key") != "key" {
+			t.Errorf("expected the API key header, got %q", r.Header.Get("X-Goog-Api-Key"))
+		}
+
+		var body struct {
+			Q      []string `json:"q"`
+			Format string   `json:"format"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Format != "html" {
+			t.Errorf("expected format html, got %s", body.Format)
+		}
+
+		var translations []map[string]string
+		for _, q := range body.Q {
+			translations = append(translations, map[string]string{"translatedText": strings.ToUpper(q), "detectedSourceLanguage": "en"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"translations": translations}})
+	}))
+	defer server.Close()
+
+	g := NewGoogleCloudTranslator("auto", "fr", nil, "key")
+	g.SetBaseURL(server.URL)
+	g.SetFormat("html")
+
+	translations, err := g.TranslateBatch([]string{"<b>save</b>", "cancel"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(translations) != 2 || translations[0] != "<B>SAVE</B>" || translations[1] != "CANCEL" {
+		t.Fatalf("unexpected translations %q", translations)
+	}
+}
+
+func TestGoogleCloudTranslateV3Glossary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/projects/acme/locations/us-central1:translateText" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected a bearer token, got %q", r.Header.Get("Authorization"))
+		}
+
+		var body struct {
+			GlossaryConfig struct {
+				Glossary string `json:"glossary"`
+			} `json:"glossaryConfig"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.GlossaryConfig.Glossary != "projects/acme/locations/us-central1/glossaries/terms" {
+			t.Errorf("unexpected glossary %s", body.GlossaryConfig.Glossary)
+		}
+
+		w.Write([]byte(`{"translations":[{"translatedText":"le widget"}],"glossaryTranslations":[{"translatedText":"le Widget"}]}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleCloudTranslator("en", "fr", nil, "")
+	g.SetBaseURL(server.URL)
+	g.SetBearerToken("token")
+	g.SetProject("acme", "us-central1")
+	g.SetGlossary("terms")
+
+	translations, err := g.TranslateDetailed(context.Background(), []string{"the widget"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if translations[0].Text != "le widget" || translations[0].GlossaryText != "le Widget" {
+		t.Fatalf("unexpected translation %+v", translations[0])
+	}
+}
+
+func TestGoogleCloudLanguages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("target") != "en" {
+			t.Errorf("expected target=en, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"data":{"languages":[{"language":"de","name":"German"},{"language":"fr","name":"French"}]}}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleCloudTranslator("en", "fr", nil, "key")
+	g.SetBaseURL(server.URL)
+
+	// the languages may be checked while they are refreshed
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			g.IsLanguageSupported("german")
+		}
+	}()
+
+	languages, err := g.Languages(context.Background(), "en")
+	<-done
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(languages) != 2 || languages[0].Code != "de" {
+		t.Fatalf("unexpected languages %+v", languages)
+	}
+
+	if !g.IsLanguageSupported("french") || g.IsLanguageSupported("klingon") {
+		t.Fatalf("expected the fetched languages to be used")
+	}
+}
+
+func TestGoogleCloudErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":403,"message":"API key not valid","status":"PERMISSION_DENIED"}}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogleCloudTranslator("en", "fr", nil, "bad")
+	g.SetBaseURL(server.URL)
+
+	_, err := g.Translate("hello")
+	if !errors.Is(err, errs.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "API key not valid") {
+		t.Fatalf("expected the API message in the error, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Everything Google's JSON endpoint returns for a single text.
+type GoogleResult struct {
+	Text                  string
+	DetectedSource        string
+	Transliteration       string
+	SourceTransliteration string
+	Dictionary            []GoogleDictionaryEntry
+	Alternatives          []Alternative
+}
+
+// Dictionary translations of a single word grouped by part of speech.
+type GoogleDictionaryEntry struct {
+	PartOfSpeech string
+	BaseForm     string
+	Terms        []GoogleDictionaryTerm
+}
+
+// A dictionary translation together with the source words it translates back to.
+type GoogleDictionaryTerm struct {
+	Word                string
+	ReverseTranslations []string
+	Score               float64
+}
+
+// Translates the text through the JSON endpoint and returns the translation together with
+// the detected source language, transliterations, dictionary entries and alternatives.
+func (gt *GoogleTranslator) TranslateDetailed(ctx context.Context, text string) (*GoogleResult, error) {
+	data, err := gt.fetchJSON(ctx, text, nil, "t", "at", "rm", "bd")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GoogleResult{
+		Text:         parseGoogleTranslation(data),
+		Dictionary:   parseGoogleDictionary(data),
+		Alternatives: parseGoogleAlternatives(data),
+	}
+	result.Transliteration, result.SourceTransliteration = parseGoogleTransliteration(data)
+	if len(data) > 2 {
+		result.DetectedSource, _ = data[2].(string)
+	}
+
+	if result.Text == "" {
+		return nil, errors.New("translation not found")
+	}
+
+	return result, nil
+}
+
+// Returns up to n alternative translations of the text, best first.
+// The scores are the ones reported by Google, averaged over the sentences of the text.
+func (gt *GoogleTranslator) Alternatives(ctx context.Context, text string, n int) ([]Alternative, error) {
+	data, err := gt.fetchJSON(ctx, text, nil, "t", "at")
+	if err != nil {
+		return nil, err
+	}
+
+	alternatives := parseGoogleAlternatives(data)
+	if len(alternatives) == 0 {
+		return nil, errors.New("translation not found")
+	}
+
+	return limitAlternatives(alternatives, n), nil
+}
+
+// Translates the text through the JSON endpoint, returning only the translation.
+func (gt *GoogleTranslator) translateJSON(ctx context.Context, text string, urlParams url.Values) (string, error) {
+	data, err := gt.fetchJSON(ctx, text, urlParams, "t")
+	if err != nil {
+		return "", err
+	}
+
+	translatedText := parseGoogleTranslation(data)
+	if translatedText == "" {
+		return "", errors.New("translation not found")
+	}
+
+	return translatedText, nil
+}
+
+// Calls Google's translate_a/single JSON endpoint asking for the given data types
+// (t for translation, at for alternatives, ...) and returns the decoded response.
+func (gt *GoogleTranslator) fetchJSON(ctx context.Context, text string, urlParams url.Values, dataTypes ...string) ([]interface{}, error) {
+	params := url.Values{}
+	for k, v := range urlParams {
+		params[k] = v
+	}
+	params.Set("client", "gtx")
+	params.Set("sl", gt.source)
+	params.Set("tl", gt.target)
+	params.Set("q", text)
+	params.Del("dt")
+	for _, dataType := range dataTypes {
+		params.Add("dt", dataType)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", gt.apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := gt.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to translate text: %s", resp.Status)
+	}
+
+	var data []interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Joins the translated sentences found at index 0 of the response.
+func parseGoogleTranslation(data []interface{}) string {
+	if len(data) == 0 {
+		return ""
+	}
+	sentences, _ := data[0].([]interface{})
+
+	var builder strings.Builder
+	for _, sentence := range sentences {
+		fields, _ := sentence.([]interface{})
+		if len(fields) == 0 {
+			continue
+		}
+		if translated, ok := fields[0].(string); ok {
+			builder.WriteString(translated)
+		}
+	}
+
+	return builder.String()
+}
+
+// Returns the target and source transliterations, which Google appends to the
+// sentences at index 0 as a row without a translation.
+func parseGoogleTransliteration(data []interface{}) (string, string) {
+	if len(data) == 0 {
+		return "", ""
+	}
+	sentences, _ := data[0].([]interface{})
+
+	var target, source strings.Builder
+	for _, sentence := range sentences {
+		fields, _ := sentence.([]interface{})
+		if len(fields) < 4 || fields[0] != nil {
+			continue
+		}
+		if translit, ok := fields[2].(string); ok {
+			target.WriteString(translit)
+		}
+		if translit, ok := fields[3].(string); ok {
+			source.WriteString(translit)
+		}
+	}
+
+	return target.String(), source.String()
+}
+
+// Reads the dictionary entries found at index 1 of the response.
+func parseGoogleDictionary(data []interface{}) []GoogleDictionaryEntry {
+	if len(data) < 2 {
+		return nil
+	}
+	groups, _ := data[1].([]interface{})
+
+	var entries []GoogleDictionaryEntry
+	for _, group := range groups {
+		fields, _ := group.([]interface{})
+		if len(fields) < 3 {
+			continue
+		}
+
+		entry := GoogleDictionaryEntry{}
+		entry.PartOfSpeech, _ = fields[0].(string)
+		if len(fields) > 3 {
+			entry.BaseForm, _ = fields[3].(string)
+		}
+
+		terms, _ := fields[2].([]interface{})
+		for _, term := range terms {
+			values, _ := term.([]interface{})
+			if len(values) == 0 {
+				continue
+			}
+
+			dictionaryTerm := GoogleDictionaryTerm{}
+			dictionaryTerm.Word, _ = values[0].(string)
+			if len(values) > 1 {
+				reverse, _ := values[1].([]interface{})
+				for _, word := range reverse {
+					if w, ok := word.(string); ok {
+						dictionaryTerm.ReverseTranslations = append(dictionaryTerm.ReverseTranslations, w)
+					}
+				}
+			}
+			if len(values) > 3 {
+				dictionaryTerm.Score, _ = values[3].(float64)
+			}
+			entry.Terms = append(entry.Terms, dictionaryTerm)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Builds full-text candidates from the per-sentence alternatives found at index 5 of the response.
+// Candidate k uses the k-th alternative of every sentence, or its best one when there are fewer.
+// The sentences are followed by the same whitespace as in the main translation, so no spaces
+// are added for targets such as Japanese which do not separate their sentences.
+func parseGoogleAlternatives(data []interface{}) []Alternative {
+	if len(data) < 6 {
+		return nil
+	}
+	segments, _ := data[5].([]interface{})
+	spacing := parseGoogleSpacing(data)
+
+	var perSegment [][]Alternative
+	var separators []string
+	most := 0
+	for i, segment := range segments {
+		fields, _ := segment.([]interface{})
+		if len(fields) < 3 {
+			continue
+		}
+		entries, _ := fields[2].([]interface{})
+
+		var candidates []Alternative
+		for _, entry := range entries {
+			values, _ := entry.([]interface{})
+			if len(values) == 0 {
+				continue
+			}
+			text, _ := values[0].(string)
+			var score float64
+			if len(values) > 1 {
+				score, _ = values[1].(float64)
+			}
+			candidates = appendAlternative(candidates, text, score)
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		perSegment = append(perSegment, candidates)
+		separator := ""
+		if i < len(spacing) {
+			separator = spacing[i]
+		}
+		separators = append(separators, separator)
+		most = max(most, len(candidates))
+	}
+
+	var alternatives []Alternative
+	for k := 0; k < most; k++ {
+		var builder strings.Builder
+		var score float64
+		for i, candidates := range perSegment {
+			candidate := candidates[0]
+			if k < len(candidates) {
+				candidate = candidates[k]
+			}
+			builder.WriteString(candidate.Text)
+			if strings.TrimRightFunc(candidate.Text, unicode.IsSpace) == candidate.Text {
+				builder.WriteString(separators[i])
+			}
+			score += candidate.Score
+		}
+		alternatives = appendAlternative(alternatives, builder.String(), score/float64(len(perSegment)))
+	}
+
+	return alternatives
+}
+
+// Returns the whitespace ending every translated sentence at index 0 of the response.
+func parseGoogleSpacing(data []interface{}) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	sentences, _ := data[0].([]interface{})
+
+	var spacing []string
+	for _, sentence := range sentences {
+		fields, _ := sentence.([]interface{})
+		if len(fields) == 0 {
+			continue
+		}
+		if translated, ok := fields[0].(string); ok {
+			spacing = append(spacing, translated[len(strings.TrimRightFunc(translated, unicode.IsSpace)):])
+		}
+	}
+
+	return spacing
+}
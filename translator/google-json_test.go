@@ -0,0 +1,140 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGoogleAlternatives(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query()["dt"]; len(got) != 2 || got[1] != "at" {
+			t.Errorf("expected dt=t&dt=at, got %v", got)
+		}
+		w.Write([]byte(`[[["Hallo Welt.","Hello world.",null,null,10]],null,"en",null,null,` +
+			`[["Hello world.",null,[["Hallo Welt.",1000,true,false],["Hallo, Welt.",500,true,false],["Hallo Erde.",0,true,false]],[[0,12]],"Hello world.",0,0]]]`))
+	}))
+	defer server.Close()
+
+	gt := NewGoogleTranslator("en", "de", nil)
+	gt.apiURL = server.URL
+
+	alternatives, err := gt.Alternatives(context.Background(), "Hello world.", 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(alternatives) != 2 {
+		t.Fatalf("expected 2 alternatives, got %d", len(alternatives))
+	}
+
+	if alternatives[0].Text != "Hallo Welt." || alternatives[0].Score != 1000 {
+		t.Fatalf("expected 'Hallo Welt.' scored 1000, got %+v", alternatives[0])
+	}
+
+	if alternatives[1].Text != "Hallo, Welt." || alternatives[1].Score != 500 {
+		t.Fatalf("expected 'Hallo, Welt.' scored 500, got %+v", alternatives[1])
+	}
+}
+
+func TestGoogleTranslateDetailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[[["こんにちは","hello",null,null,10],[null,null,"Kon'nichiwa",null]],` +
+			`[["interjection",["こんにちは","やあ"],[["こんにちは",["hello","hi"],null,0.5],["やあ",["hi"],null,0.1]],"hello",9]],` +
+			`"en"]`))
+	}))
+	defer server.Close()
+
+	gt := NewGoogleTranslator("auto", "ja", nil)
+	gt.apiURL = server.URL
+
+	result, err := gt.TranslateDetailed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Text != "こんにちは" {
+		t.Fatalf("expected 'こんにちは', got %s", result.Text)
+	}
+
+	if result.DetectedSource != "en" {
+		t.Fatalf("expected detected source 'en', got %s", result.DetectedSource)
+	}
+
+	if result.Transliteration != "Kon'nichiwa" {
+		t.Fatalf("expected transliteration 'Kon'nichiwa', got %s", result.Transliteration)
+	}
+
+	if len(result.Dictionary) != 1 || result.Dictionary[0].PartOfSpeech != "interjection" {
+		t.Fatalf("expected one interjection dictionary entry, got %+v", result.Dictionary)
+	}
+
+	terms := result.Dictionary[0].Terms
+	if len(terms) != 2 || terms[1].Word != "やあ" || terms[0].ReverseTranslations[1] != "hi" {
+		t.Fatalf("unexpected dictionary terms %+v", terms)
+	}
+}
+
+func TestGoogleModeAutoFallsBackToHTML(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer api.Close()
+
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><div class="result-container">Bonjour</div></body></html>`))
+	}))
+	defer page.Close()
+
+	gt := NewGoogleTranslator("en", "fr", nil)
+	gt.apiURL = api.URL
+	gt.baseURL = page.URL
+
+	translated, err := gt.Translate("Hello")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if translated != "Bonjour" {
+		t.Fatalf("expected 'Bonjour', got %s", translated)
+	}
+
+	gt.SetMode(GoogleModeJSON)
+	if _, err := gt.Translate("Hello"); err == nil {
+		t.Fatalf("expected an error without the HTML fallback, got nil")
+	}
+}
+
+func TestGoogleAlternativesKeepSpacing(t *testing.T) {
+	responses := map[string]string{
+		// Japanese does not separate its sentences with spaces
+		"ja": `[[["こんにちは。","Hello.",null,null,10],["お元気ですか？","How are you?",null,null,10]],null,"en",null,null,` +
+			`[["Hello.",null,[["こんにちは。",900,true,false]],[[0,6]],"Hello.",0,0],` +
+			`["How are you?",null,[["お元気ですか？",800,true,false],["元気？",400,true,false]],[[7,19]],"How are you?",0,0]]]`,
+		"de": `[[["Hallo. ","Hello. ",null,null,10],["Wie geht es dir?","How are you?",null,null,10]],null,"en",null,null,` +
+			`[["Hello.",null,[["Hallo.",900,true,false]],[[0,6]],"Hello.",0,0],` +
+			`["How are you?",null,[["Wie geht es dir?",800,true,false],["Wie geht's?",400,true,false]],[[7,19]],"How are you?",0,0]]]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responses[r.URL.Query().Get("tl")]))
+	}))
+	defer server.Close()
+
+	expected := map[string][]string{
+		"ja": {"こんにちは。お元気ですか？", "こんにちは。元気？"},
+		"de": {"Hallo. Wie geht es dir?", "Hallo. Wie geht's?"},
+	}
+	for target, texts := range expected {
+		gt := NewGoogleTranslator("en", target, nil)
+		gt.apiURL = server.URL
+
+		alternatives, err := gt.Alternatives(context.Background(), "Hello. How are you?", 0)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", target, err)
+		}
+		if len(alternatives) != 2 || alternatives[0].Text != texts[0] || alternatives[1].Text != texts[1] {
+			t.Fatalf("%s: expected %q, got %+v", target, texts, alternatives)
+		}
+	}
+}
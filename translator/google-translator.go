@@ -1,6 +1,7 @@
 package translator
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -9,15 +10,31 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/kashari/go-translate/bread"
 	"github.com/kashari/go-translate/constants"
 	errs "github.com/kashari/go-translate/errors"
 )
 
+// Selects which Google endpoint a GoogleTranslator talks to.
+type GoogleMode int
+
+const (
+	// Uses the JSON endpoint and falls back to scraping the HTML page when it fails.
+	GoogleModeAuto GoogleMode = iota
+	// Uses only the translate_a/single JSON endpoint.
+	GoogleModeJSON
+	// Uses only the translate.google.com/m HTML page.
+	GoogleModeHTML
+)
+
 // Represents a translator using Google Translate under the hood.
 type GoogleTranslator struct {
 	baseURL            string
+	apiURL             string
+	mode               GoogleMode
 	source             string
 	target             string
 	proxies            *url.URL
@@ -34,6 +51,7 @@ type GoogleTranslator struct {
 func NewGoogleTranslator(source, target string, proxies *url.URL) *GoogleTranslator {
 	return &GoogleTranslator{
 		baseURL:            constants.BASE_URLS["GOOGLE_TRANSLATE"],
+		apiURL:             constants.BASE_URLS["GOOGLE_TRANSLATE_API"],
 		source:             source,
 		target:             target,
 		proxies:            proxies,
@@ -43,51 +61,18 @@ func NewGoogleTranslator(source, target string, proxies *url.URL) *GoogleTransla
 		altElementQuery:    map[string]string{"class": "result-container"},
 		urlParams:          url.Values{},
 		supportedLanguages: constants.GOOGLE_LANGUAGES_TO_CODES,
-		client:             &http.Client{},
+		client:             newHTTPClient(proxies),
 	}
 }
 
+// Sets the endpoint used by Translate and the other text methods.
+func (gt *GoogleTranslator) SetMode(mode GoogleMode) {
+	gt.mode = mode
+}
+
 // Translates the given text from the source language to the target language.
 func (gt *GoogleTranslator) Translate(text string) (string, error) {
-	if len(strings.TrimSpace(text)) == 0 || len(text) > 5000 {
-		errs.TooLongTextError()
-		return "", errors.New("invalid input text")
-	}
-
-	if gt.source == gt.target {
-		return text, nil
-	}
-
-	gt.urlParams.Set("tl", gt.target)
-	gt.urlParams.Set("sl", gt.source)
-	gt.urlParams.Set(gt.payloadKey, text)
-
-	resp, err := gt.client.Get(gt.baseURL + "?" + gt.urlParams.Encode())
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	body, err := bread.GetWithClient(gt.baseURL+"?"+gt.urlParams.Encode(), gt.client)
-	if err != nil {
-		return "", err
-	}
-
-	doc := bread.HTMLParse(body)
-	element := doc.Find(gt.elementTag, "class", gt.elementQuery["class"])
-	if element.Error != nil {
-		element = doc.Find(gt.elementTag, "class", gt.altElementQuery["class"])
-		if element.Error != nil {
-			return "", errors.New("translation not found")
-		}
-	}
-
-	translatedText := element.FullText()
-	if strings.TrimSpace(translatedText) == strings.TrimSpace(text) {
-		return text, nil
-	}
-
-	return translatedText, nil
+	return gt.TranslateWithParams(text, gt.urlParams)
 }
 
 // Translates the text from the given file path.
@@ -166,7 +151,7 @@ func (gt *GoogleTranslator) TranslateFile(path string) (*os.File, error) {
 
 // Translates the given text with the provided URL parameters.
 func (gt *GoogleTranslator) TranslateWithParams(text string, urlParams url.Values) (string, error) {
-	if len(strings.TrimSpace(text)) == 0 || len(text) > 5000 {
+	if len(strings.TrimSpace(text)) == 0 || utf8.RuneCountInString(text) > googleMaxChars {
 		errs.TooLongTextError()
 		return "", errors.New("invalid input text")
 	}
@@ -175,22 +160,52 @@ func (gt *GoogleTranslator) TranslateWithParams(text string, urlParams url.Value
 		return text, nil
 	}
 
-	urlParams.Set("tl", gt.target)
-	urlParams.Set("sl", gt.source)
-	urlParams.Set(gt.payloadKey, text)
-
-	resp, err := gt.client.Get(gt.baseURL + "?" + urlParams.Encode())
+	ctx := context.Background()
+	var translatedText string
+	var err error
+	switch gt.mode {
+	case GoogleModeJSON:
+		translatedText, err = gt.translateJSON(ctx, text, urlParams)
+	case GoogleModeHTML:
+		translatedText, err = gt.scrape(ctx, text, urlParams)
+	default:
+		translatedText, err = gt.translateJSON(ctx, text, urlParams)
+		if err != nil {
+			translatedText, err = gt.scrape(ctx, text, urlParams)
+		}
+	}
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
 
-	body, err := bread.GetWithClient(gt.baseURL+"?"+urlParams.Encode(), gt.client)
+	if strings.TrimSpace(translatedText) == strings.TrimSpace(text) {
+		return text, nil
+	}
+
+	return translatedText, nil
+}
+
+// Translates the text by scraping the result out of the mobile HTML page.
+// The page is fetched with a single request and parsed once.
+func (gt *GoogleTranslator) scrape(ctx context.Context, text string, urlParams url.Values) (string, error) {
+	params := url.Values{}
+	for k, v := range urlParams {
+		params[k] = v
+	}
+	params.Set("tl", gt.target)
+	params.Set("sl", gt.source)
+	params.Set(gt.payloadKey, text)
+
+	body, err := bread.GetWithContext(ctx, gt.baseURL+"?"+params.Encode(), gt.client)
 	if err != nil {
 		return "", err
 	}
 
 	doc := bread.HTMLParse(body)
+	if doc.Error != nil {
+		return "", doc.Error
+	}
+
 	element := doc.Find(gt.elementTag, "class", gt.elementQuery["class"])
 	if element.Error != nil {
 		element = doc.Find(gt.elementTag, "class", gt.altElementQuery["class"])
@@ -199,60 +214,31 @@ func (gt *GoogleTranslator) TranslateWithParams(text string, urlParams url.Value
 		}
 	}
 
-	translatedText := element.FullText()
-	if strings.TrimSpace(translatedText) == strings.TrimSpace(text) {
-		return text, nil
-	}
-
-	return translatedText, nil
+	return element.FullText(), nil
 }
 
+// Separates the texts packed into a single request. It has nothing to translate,
+// so Google returns it untouched between the translated texts.
+const googleBatchSeparator = "|||"
+
+// Longest text Google translates in a single request, in characters.
+const googleMaxChars = 5000
+
 // Translates a batch of texts.
+// Short texts are packed together into requests of up to 5000 characters and split
+// apart again afterwards. When the translated request does not split back into the
+// same number of texts, the texts of that request are translated one by one.
 func (gt *GoogleTranslator) TranslateBatch(batch []string) ([]string, error) {
 	var wg sync.WaitGroup
 	translations := make([]string, len(batch))
-	ch := make(chan struct {
-		index int
-		text  string
-		err   error
-	}, len(batch))
-
-	for i, text := range batch {
-		if len(text) > 5000 {
-			// split the text in chunks of 5000 characters
-			var chunks []string
-			for i := 0; i < len(text); i += 5000 {
-				end := i + 5000
-				if end > len(text) {
-					end = len(text)
-				}
-				chunks = append(chunks, text[i:end])
-			}
-			wg.Add(len(chunks))
-			for j, chunk := range chunks {
-				go func(i, j int, text string) {
-					defer wg.Done()
-					translated, err := gt.Translate(text)
-					ch <- struct {
-						index int
-						text  string
-						err   error
-					}{i, translated, err}
-				}(i, j, chunk)
-			}
-			continue
-		}
+	ch := make(chan error, len(batch))
 
+	for _, group := range packGoogleBatch(batch) {
 		wg.Add(1)
-		go func(i int, text string) {
+		go func(group []int) {
 			defer wg.Done()
-			translated, err := gt.Translate(text)
-			ch <- struct {
-				index int
-				text  string
-				err   error
-			}{i, translated, err}
-		}(i, text)
+			ch <- gt.translateGroup(batch, group, translations)
+		}(group)
 	}
 
 	go func() {
@@ -260,16 +246,124 @@ func (gt *GoogleTranslator) TranslateBatch(batch []string) ([]string, error) {
 		close(ch)
 	}()
 
-	for result := range ch {
-		if result.err != nil {
-			return nil, result.err
+	for err := range ch {
+		if err != nil {
+			return nil, err
 		}
-		translations[result.index] = result.text
 	}
 
 	return translations, nil
 }
 
+// Groups the indexes of the texts that can share a request, keeping their order.
+// Blank texts, texts over the limit and texts containing the separator get their own group.
+func packGoogleBatch(batch []string) [][]int {
+	var groups [][]int
+	var current []int
+	size := 0
+
+	for i, text := range batch {
+		if len(strings.TrimSpace(text)) == 0 || strings.Contains(text, googleBatchSeparator) || utf8.RuneCountInString(text) > googleMaxChars {
+			groups = append(groups, []int{i})
+			continue
+		}
+
+		joined := size + utf8.RuneCountInString(text)
+		if len(current) > 0 {
+			joined += len(googleBatchSeparator) + 2
+		}
+		if joined > googleMaxChars {
+			groups = append(groups, current)
+			current = nil
+			joined = utf8.RuneCountInString(text)
+		}
+
+		current = append(current, i)
+		size = joined
+	}
+
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+
+	return groups
+}
+
+// Translates the texts of a group and stores the results at their indexes.
+func (gt *GoogleTranslator) translateGroup(batch []string, group []int, translations []string) error {
+	if len(group) == 1 {
+		translated, err := gt.translateItem(batch[group[0]])
+		if err != nil {
+			return err
+		}
+		translations[group[0]] = translated
+		return nil
+	}
+
+	texts := make([]string, len(group))
+	for i, index := range group {
+		texts[i] = batch[index]
+	}
+
+	translated, err := gt.Translate(strings.Join(texts, "\n"+googleBatchSeparator+"\n"))
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(translated, googleBatchSeparator)
+	if len(parts) != len(group) {
+		for _, index := range group {
+			translated, err := gt.translateItem(batch[index])
+			if err != nil {
+				return err
+			}
+			translations[index] = translated
+		}
+		return nil
+	}
+
+	for i, index := range group {
+		translations[index] = keepSurroundingSpace(batch[index], strings.TrimSpace(parts[i]))
+	}
+
+	return nil
+}
+
+// Translates a single text of a batch, splitting it into chunks of up to 5000 characters
+// when needed. Blank chunks are kept as they are.
+func (gt *GoogleTranslator) translateItem(text string) (string, error) {
+	if len(strings.TrimSpace(text)) == 0 {
+		return text, nil
+	}
+
+	if utf8.RuneCountInString(text) <= googleMaxChars {
+		return gt.Translate(text)
+	}
+
+	var builder strings.Builder
+	for _, chunk := range splitText(text, googleMaxChars, utf8.RuneCountInString) {
+		if len(strings.TrimSpace(chunk)) == 0 {
+			builder.WriteString(chunk)
+			continue
+		}
+		translated, err := gt.Translate(chunk)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(keepSurroundingSpace(chunk, strings.TrimSpace(translated)))
+	}
+
+	return builder.String(), nil
+}
+
+// Puts the leading and trailing whitespace of the original text back around its translation.
+func keepSurroundingSpace(original, translated string) string {
+	trimmed := strings.TrimLeftFunc(original, unicode.IsSpace)
+	leading := original[:len(original)-len(trimmed)]
+	trailing := trimmed[len(strings.TrimRightFunc(trimmed, unicode.IsSpace)):]
+	return leading + translated + trailing
+}
+
 // Maps languages to their corresponding codes
 func (bt *GoogleTranslator) MapLanguageToCode(languages ...string) (string, string) {
 	var mappedLanguages []string
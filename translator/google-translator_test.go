@@ -0,0 +1,217 @@
+package translator
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kashari/go-translate/bread"
+)
+
+const googlePage = `<html><body><div class="result-container">Bonjour</div></body></html>`
+
+// Starts a fake Google page counting the requests it receives, every response is delayed
+// to make the cost of an extra round trip visible in the benchmarks.
+func newGoogleServer(tb testing.TB, latency time.Duration, requests *int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(requests, 1)
+		time.Sleep(latency)
+		if r.URL.Query().Get("client") == "gtx" {
+			w.Write([]byte(`[[["Bonjour","Hello",null,null,10]],null,"en"]`))
+			return
+		}
+		w.Write([]byte(googlePage))
+	}))
+	tb.Cleanup(server.Close)
+	return server
+}
+
+func newTestGoogleTranslator(server *httptest.Server, mode GoogleMode) *GoogleTranslator {
+	gt := NewGoogleTranslator("en", "fr", nil)
+	gt.baseURL = server.URL
+	gt.apiURL = server.URL
+	gt.SetMode(mode)
+	return gt
+}
+
+func TestGoogleTranslateSingleRequest(t *testing.T) {
+	for _, mode := range []GoogleMode{GoogleModeAuto, GoogleModeJSON, GoogleModeHTML} {
+		var requests int64
+		gt := newTestGoogleTranslator(newGoogleServer(t, 0, &requests), mode)
+
+		translated, err := gt.Translate("Hello")
+		if err != nil {
+			t.Fatalf("mode %d: expected no error, got %v", mode, err)
+		}
+
+		if translated != "Bonjour" {
+			t.Fatalf("mode %d: expected 'Bonjour', got %s", mode, translated)
+		}
+
+		if requests != 1 {
+			t.Fatalf("mode %d: expected 1 request, got %d", mode, requests)
+		}
+	}
+}
+
+func benchmarkGoogleTranslate(b *testing.B, mode GoogleMode) {
+	var requests int64
+	gt := newTestGoogleTranslator(newGoogleServer(b, time.Millisecond, &requests), mode)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gt.Translate("Hello"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(requests)/float64(b.N), "requests/op")
+}
+
+func BenchmarkGoogleTranslateHTML(b *testing.B) {
+	benchmarkGoogleTranslate(b, GoogleModeHTML)
+}
+
+func BenchmarkGoogleTranslateJSON(b *testing.B) {
+	benchmarkGoogleTranslate(b, GoogleModeJSON)
+}
+
+// Reproduces the previous request path, which fetched the page twice on a client
+// without a shared transport, as a baseline for the benchmarks above.
+func BenchmarkGoogleTranslateDoubleRequest(b *testing.B) {
+	var requests int64
+	server := newGoogleServer(b, time.Millisecond, &requests)
+	client := &http.Client{Transport: &http.Transport{}}
+
+	params := url.Values{}
+	params.Set("sl", "en")
+	params.Set("tl", "fr")
+	params.Set("q", "Hello")
+	pageURL := server.URL + "?" + params.Encode()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := client.Get(pageURL)
+		if err != nil {
+			b.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		body, err := bread.GetWithClient(pageURL, client)
+		if err != nil {
+			b.Fatal(err)
+		}
+		doc := bread.HTMLParse(body)
+		if doc.Find("div", "class", "result-container").Error != nil {
+			b.Fatal("translation not found")
+		}
+	}
+	b.ReportMetric(float64(requests)/float64(b.N), "requests/op")
+}
+
+// Starts a fake JSON endpoint upper-casing the text, optionally dropping the batch separator.
+func newUpperCaseServer(tb testing.TB, dropSeparator bool, requests *int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(requests, 1)
+		q := r.URL.Query().Get("q")
+		if dropSeparator {
+			q = strings.ReplaceAll(q, googleBatchSeparator, "")
+		}
+		response, _ := json.Marshal([]interface{}{[]interface{}{[]interface{}{strings.ToUpper(q), q}}})
+		w.Write(response)
+	}))
+	tb.Cleanup(server.Close)
+	return server
+}
+
+func TestGoogleTranslateBatchPacksTexts(t *testing.T) {
+	var requests int64
+	gt := newTestGoogleTranslator(newUpperCaseServer(t, false, &requests), GoogleModeJSON)
+
+	batch := []string{"save", " cancel ", "", "open file"}
+	translations, err := gt.TranslateBatch(batch)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"SAVE", " CANCEL ", "", "OPEN FILE"}
+	for i := range expected {
+		if translations[i] != expected[i] {
+			t.Fatalf("expected %q at %d, got %q", expected[i], i, translations[i])
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestGoogleTranslateBatchFallsBackPerItem(t *testing.T) {
+	var requests int64
+	gt := newTestGoogleTranslator(newUpperCaseServer(t, true, &requests), GoogleModeJSON)
+
+	translations, err := gt.TranslateBatch([]string{"save", "cancel", "open"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if translations[0] != "SAVE" || translations[1] != "CANCEL" || translations[2] != "OPEN" {
+		t.Fatalf("unexpected translations %q", translations)
+	}
+
+	if requests != 4 {
+		t.Fatalf("expected 1 packed and 3 single requests, got %d", requests)
+	}
+}
+
+func TestPackGoogleBatchRespectsLimit(t *testing.T) {
+	batch := []string{strings.Repeat("a", 3000), strings.Repeat("b", 1996), strings.Repeat("c", 10), "d"}
+
+	groups := packGoogleBatch(batch)
+	if len(groups) != 2 || len(groups[0]) != 1 || len(groups[1]) != 3 {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+}
+
+func TestGoogleTranslateBatchSplitsLongTexts(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		q := r.URL.Query().Get("q")
+		if !utf8.ValidString(q) || utf8.RuneCountInString(q) > googleMaxChars || strings.TrimSpace(q) == "" {
+			t.Errorf("unexpected chunk of %d characters", utf8.RuneCountInString(q))
+		}
+		response, _ := json.Marshal([]interface{}{[]interface{}{[]interface{}{strings.ToUpper(q), q}}})
+		w.Write(response)
+	}))
+	defer server.Close()
+	gt := newTestGoogleTranslator(server, GoogleModeJSON)
+
+	// the whitespace left after the first chunk is passed through
+	spaceTail := strings.Repeat("a", googleMaxChars) + " "
+	// 6000 characters but more than 10000 bytes
+	cyrillic := strings.Repeat("дом ", 1500)
+
+	translations, err := gt.TranslateBatch([]string{spaceTail, cyrillic})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if translations[0] != strings.ToUpper(spaceTail) {
+		t.Fatalf("unexpected translation of %d characters", utf8.RuneCountInString(translations[0]))
+	}
+	if translations[1] != strings.ToUpper(cyrillic) {
+		t.Fatalf("expected the Cyrillic text back whole, got %d characters", utf8.RuneCountInString(translations[1]))
+	}
+
+	if requests != 3 {
+		t.Fatalf("expected 1 request for the first text and 2 for the second, got %d", requests)
+	}
+}
@@ -0,0 +1,166 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Transport shared by the translators so that connections are kept alive and reused
+// across requests and instances. HTTP/2 is negotiated whenever the server supports it.
+var sharedTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   32,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// Returns a client on the shared transport, or on a copy of it going through the proxy.
+func newHTTPClient(proxies *url.URL) *http.Client {
+	if proxies == nil {
+		return &http.Client{Transport: sharedTransport}
+	}
+
+	transport := sharedTransport.Clone()
+	transport.Proxy = http.ProxyURL(proxies)
+	return &http.Client{Transport: transport}
+}
+
+// Returned when a translation API answers with an unsuccessful status.
+// It unwraps to the matching errs value, errs.ErrTooManyRequests for a 429 for example,
+// so callers can check it with errors.Is.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func newStatusError(resp *http.Response, message string) *StatusError {
+	return &StatusError{StatusCode: resp.StatusCode, Message: message}
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return errs.ErrUnauthorized
+	case e.StatusCode == http.StatusRequestEntityTooLarge || e.StatusCode == http.StatusRequestURITooLong:
+		return errs.ErrTooLongText
+	case e.StatusCode == http.StatusTooManyRequests:
+		return errs.ErrTooManyRequests
+	// DeepL answers 456 once the character quota is used up
+	case e.StatusCode == 456:
+		return errs.ErrQuotaExceeded
+	case e.StatusCode >= 500:
+		return errs.ErrRequest
+	}
+	return nil
+}
+
+// Builds a POST request whose multipart form holds the fields and, as its "file" part, the
+// content of r. The form is written through a pipe while the request is sent, so a document
+// is never held in memory. The caller closes the body when the request is not sent.
+func streamMultipart(ctx context.Context, rawURL string, fields url.Values, filename string, r io.Reader) (*http.Request, error) {
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		for key := range fields {
+			if err := form.WriteField(key, fields.Get(key)); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+		part, err := form.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", rawURL, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return req, nil
+}
+
+// Groups consecutive texts into chunks of at most maxTexts texts whose sizes, as measured by
+// size, add up to at most maxSize, so that every chunk fits in a single request.
+// A text too large for a request of its own is refused with errs.ErrTooLongText.
+func chunkTexts(texts []string, maxTexts, maxSize int, size func(string) int) ([][]string, error) {
+	var chunks [][]string
+	start, total := 0, 0
+	for i, text := range texts {
+		n := size(text)
+		if n > maxSize {
+			return nil, fmt.Errorf("text %d exceeds the limit of %d per request: %w", i, maxSize, errs.ErrTooLongText)
+		}
+
+		if i > start && (i-start == maxTexts || total+n > maxSize) {
+			chunks = append(chunks, texts[start:i])
+			start, total = i, 0
+		}
+		total += n
+	}
+
+	if start < len(texts) {
+		chunks = append(chunks, texts[start:])
+	}
+	return chunks, nil
+}
+
+// Splits the text into chunks whose size, as measured by size, is at most limit, cutting
+// after the last line break or space of a chunk so that words are kept whole whenever
+// possible. Runes are never cut in half.
+func splitText(text string, limit int, size func(string) int) []string {
+	var chunks []string
+	for size(text) > limit {
+		end, n := 0, 0
+		for end < len(text) {
+			_, width := utf8.DecodeRuneInString(text[end:])
+			if n += size(text[end : end+width]); n > limit {
+				break
+			}
+			end += width
+		}
+		if end == 0 {
+			_, end = utf8.DecodeRuneInString(text)
+		}
+
+		if cut := strings.LastIndex(text[:end], "\n"); cut > 0 {
+			end = cut + 1
+		} else if cut := strings.LastIndex(text[:end], " "); cut > 0 {
+			end = cut + 1
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return append(chunks, text)
+}
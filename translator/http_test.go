@@ -0,0 +1,27 @@
+package translator
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+func TestChunkTexts(t *testing.T) {
+	texts := []string{"aaaa", "bb", "cc", "d", "e", "f"}
+
+	chunks, err := chunkTexts(texts, 3, 6, utf8.RuneCountInString)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// split by size first, then by count
+	if len(chunks) != 3 || len(chunks[0]) != 2 || len(chunks[1]) != 3 || chunks[2][0] != "f" {
+		t.Fatalf("unexpected chunks %q", chunks)
+	}
+
+	if _, err := chunkTexts([]string{"a", "too long"}, 3, 6, utf8.RuneCountInString); !errors.Is(err, errs.ErrTooLongText) {
+		t.Fatalf("expected ErrTooLongText, got %v", err)
+	}
+}
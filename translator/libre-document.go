@@ -0,0 +1,124 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File types accepted by the translate_file endpoint.
+var libreDocumentTypes = map[string]bool{
+	".docx": true, ".odt": true, ".pptx": true, ".odp": true, ".txt": true, ".html": true, ".htm": true,
+}
+
+// Translates the document at the path and writes the result next to it, the target language
+// being inserted before the extension, doc.docx becoming doc.fr.docx for French.
+// Returns the path of the translated document.
+func (l *LibreTranslator) TranslateDocument(ctx context.Context, path string) (string, error) {
+	ext := filepath.Ext(path)
+	output := strings.TrimSuffix(path, ext) + "." + l.target + ext
+
+	out, err := os.Create(output)
+	if err != nil {
+		return "", err
+	}
+
+	err = l.translateDocument(ctx, path, out)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(output)
+		return "", err
+	}
+
+	return output, nil
+}
+
+// Uploads the document at the path and writes its translation to w.
+func (l *LibreTranslator) translateDocument(ctx context.Context, path string, w io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileURL, err := l.UploadDocument(ctx, file, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	return l.DownloadDocument(ctx, fileURL, w)
+}
+
+// Uploads a document to the translate_file endpoint, the file name telling the server its type,
+// and returns the URL of the translated document.
+func (l *LibreTranslator) UploadDocument(ctx context.Context, r io.Reader, filename string) (string, error) {
+	if !libreDocumentTypes[strings.ToLower(filepath.Ext(filename))] {
+		return "", fmt.Errorf("unsupported document type %s", filename)
+	}
+
+	fields := url.Values{}
+	fields.Set("source", l.source)
+	fields.Set("target", l.target)
+	if l.apiKey != "" {
+		fields.Set("api_key", l.apiKey)
+	}
+
+	req, err := streamMultipart(ctx, l.baseURL+"translate_file", fields, filename, r)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := l.send(req)
+	if err != nil {
+		req.Body.Close()
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		TranslatedFileURL string `json:"translatedFileUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+	if response.TranslatedFileURL == "" {
+		return "", fmt.Errorf("no translated file returned for %s", filename)
+	}
+
+	return response.TranslatedFileURL, nil
+}
+
+// Downloads a translated document and writes it to w. Relative URLs are resolved
+// against the base URL of the translator.
+func (l *LibreTranslator) DownloadDocument(ctx context.Context, fileURL string, w io.Writer) error {
+	base, err := url.Parse(l.baseURL)
+	if err != nil {
+		return err
+	}
+	ref, err := url.Parse(fileURL)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", base.ResolveReference(ref).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := l.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
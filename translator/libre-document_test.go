@@ -0,0 +1,91 @@
+package translator
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLibreTranslateDocument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/translate_file":
+			if r.FormValue("target") != "fr" || r.FormValue("api_key") != "key" {
+				t.Errorf("unexpected form %v", r.MultipartForm)
+			}
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("expected a file, got %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(file)
+			if header.Filename != "notes.txt" || string(content) != "hello" {
+				t.Errorf("unexpected upload %s: %q", header.Filename, content)
+			}
+			// relative URLs are resolved against the base URL
+			w.Write([]byte(`{"translatedFileUrl":"/download_file/abc/notes.txt"}`))
+		case "/download_file/abc/notes.txt":
+			w.Write([]byte("bonjour"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLibreTranslator("en", "fr", nil)
+	l.SetBaseURL(server.URL)
+	l.SetAPIKey("key")
+
+	output, err := l.TranslateDocument(context.Background(), path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if output != filepath.Join(dir, "notes.fr.txt") {
+		t.Fatalf("expected the output next to the source, got %s", output)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "bonjour" {
+		t.Fatalf("expected 'bonjour', got %q", content)
+	}
+
+	// TranslateFile returns the translated document instead of writing it
+	translated, err := l.TranslateFile(path)
+	if err != nil || translated != "bonjour" {
+		t.Fatalf("expected 'bonjour', got %q %v", translated, err)
+	}
+}
+
+func TestLibreUploadUnsupportedDocument(t *testing.T) {
+	l := NewLibreTranslator("en", "fr", nil)
+
+	_, err := l.UploadDocument(context.Background(), strings.NewReader(""), "image.png")
+	if err == nil {
+		t.Fatalf("expected an error for an unsupported type, got nil")
+	}
+
+	// binary files are not sent as text either
+	path := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(path, []byte{0x89, 'P', 'N', 'G', 0, 0, 0, 0x0d}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.TranslateFile(path); err == nil || !strings.Contains(err.Error(), "not a text file") {
+		t.Fatalf("expected an error for a binary file, got %v", err)
+	}
+}
@@ -2,13 +2,17 @@ package translator
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"sync"
+	"path/filepath"
+	"strings"
 
 	"github.com/kashari/go-translate/constants"
+	errs "github.com/kashari/go-translate/errors"
 )
 
 type LibreTranslator struct {
@@ -17,89 +21,245 @@ type LibreTranslator struct {
 	target  string
 	proxies *url.URL
 	client  *http.Client
+	apiKey  string
+	format  string
 }
 
+// A translated text together with the language LibreTranslate detected for an auto source.
+type LibreTranslation struct {
+	Text             string
+	DetectedLanguage *LibreDetection
+}
+
+// A language detected by LibreTranslate, Confidence going from 0 to 100.
+type LibreDetection struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// A language supported by the server together with the languages it can be translated to.
+type LibreLanguage struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+}
+
+// Body of the translate requests, q being a single text or a list of texts.
+type libreRequest struct {
+	Q            interface{} `json:"q"`
+	Source       string      `json:"source"`
+	Target       string      `json:"target"`
+	Format       string      `json:"format"`
+	Alternatives int         `json:"alternatives,omitempty"`
+	APIKey       string      `json:"api_key,omitempty"`
+}
+
+// Creates a new instance of LibreTranslator using the public libretranslate.de server.
 func NewLibreTranslator(source, target string, proxies *url.URL) *LibreTranslator {
 	return &LibreTranslator{
-		baseURL: constants.BASE_URLS["LIBRE_FREE"],
+		baseURL: strings.TrimSuffix(constants.BASE_URLS["LIBRE_FREE"], "translate"),
 		source:  source,
 		target:  target,
 		proxies: proxies,
-		client:  &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxies)}},
+		client:  newHTTPClient(proxies),
+		format:  "text",
 	}
 }
 
-func (l *LibreTranslator) Translate(text string) (string, error) {
-	body := json.RawMessage(`{"q":"` + text + `","source":"` + l.source + `","target":"` + l.target + `"}`)
+// Sets the root URL of the server, such as http://localhost:5000 for a self-hosted instance.
+func (l *LibreTranslator) SetBaseURL(baseURL string) {
+	l.baseURL = strings.TrimRight(baseURL, "/") + "/"
+}
 
-	req, err := http.NewRequest("POST", l.baseURL, bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
-	}
+// Sets the API key sent with every request, required by servers running with --api-keys.
+func (l *LibreTranslator) SetAPIKey(apiKey string) {
+	l.apiKey = apiKey
+}
 
-	req.Header.Set("Content-Type", "application/json")
+// Sets the format of the texts, either "text" or "html".
+func (l *LibreTranslator) SetFormat(format string) {
+	l.format = format
+}
 
-	resp, err := l.client.Do(req)
+func (l *LibreTranslator) Translate(text string) (string, error) {
+	translations, err := l.TranslateDetailed(context.Background(), []string{text})
 	if err != nil {
 		return "", err
 	}
 
-	defer resp.Body.Close()
+	return translations[0].Text, nil
+}
+
+// Returns up to n candidate translations, the main translation first followed by the
+// ones from the alternatives parameter, n <= 0 asks for three. LibreTranslate does not
+// score its candidates.
+func (l *LibreTranslator) Alternatives(ctx context.Context, text string, n int) ([]Alternative, error) {
+	if n <= 0 {
+		n = 3
+	}
 
 	var response struct {
-		TranslatedText string `json:"translatedText"`
+		TranslatedText string   `json:"translatedText"`
+		Alternatives   []string `json:"alternatives"`
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		return "", err
+	body := l.request(text)
+	body.Alternatives = n - 1
+	if err := l.doJSON(ctx, "POST", "translate", body, &response); err != nil {
+		return nil, err
+	}
+
+	alternatives := appendAlternative(nil, response.TranslatedText, 0)
+	for _, alternative := range response.Alternatives {
+		alternatives = appendAlternative(alternatives, alternative, 0)
 	}
 
-	return response.TranslatedText, nil
+	return limitAlternatives(alternatives, n), nil
 }
 
+// Translates all the texts with a single request.
 func (l *LibreTranslator) TranslateBatch(texts []string) ([]string, error) {
-	var wg sync.WaitGroup
-	translations := make([]string, len(texts))
-	ch := make(chan struct {
-		index int
-		text  string
-		err   error
-	})
-
-	for i, text := range texts {
-		wg.Add(1)
-		go func(i int, text string) {
-			defer wg.Done()
-			translated, err := l.Translate(text)
-			ch <- struct {
-				index int
-				text  string
-				err   error
-			}{i, translated, err}
-		}(i, text)
-	}
-
-	go func() {
-		wg.Wait()
-		close(ch)
-	}()
-
-	for t := range ch {
-		if t.err != nil {
-			return nil, t.err
+	translations, err := l.TranslateDetailed(context.Background(), texts)
+	if err != nil {
+		return nil, err
+	}
+
+	translatedTexts := make([]string, len(translations))
+	for i, translation := range translations {
+		translatedTexts[i] = translation.Text
+	}
+	return translatedTexts, nil
+}
+
+// Translates the texts with a single request and returns the translations in the same order,
+// with the detected languages when the source is auto.
+func (l *LibreTranslator) TranslateDetailed(ctx context.Context, texts []string) ([]LibreTranslation, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	var response struct {
+		TranslatedText   []string          `json:"translatedText"`
+		DetectedLanguage []*LibreDetection `json:"detectedLanguage"`
+	}
+
+	if err := l.doJSON(ctx, "POST", "translate", l.request(texts), &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d: %w", len(texts), len(response.TranslatedText), errs.ErrTranslationNotFound)
+	}
+
+	translations := make([]LibreTranslation, len(texts))
+	for i, text := range response.TranslatedText {
+		translations[i].Text = text
+		if i < len(response.DetectedLanguage) {
+			translations[i].DetectedLanguage = response.DetectedLanguage[i]
 		}
-		translations[t.index] = t.text
 	}
 
 	return translations, nil
 }
 
+// Translates a file, the document types going through the translate_file endpoint and their
+// translated content being returned as is. Any other file must hold UTF-8 text.
+// TranslateDocument writes the translated document next to the original instead.
 func (l *LibreTranslator) TranslateFile(path string) (string, error) {
-	text, err := os.ReadFile(path)
+	if libreDocumentTypes[strings.ToLower(filepath.Ext(path))] {
+		var translated strings.Builder
+		if err := l.translateDocument(context.Background(), path, &translated); err != nil {
+			return "", err
+		}
+		return translated.String(), nil
+	}
+
+	text, err := readTextFile(path)
 	if err != nil {
 		return "", err
 	}
 
-	return l.Translate(string(text))
+	return l.Translate(text)
+}
+
+// Detects the language of the text, the most likely candidate first.
+func (l *LibreTranslator) Detect(ctx context.Context, text string) ([]LibreDetection, error) {
+	body := map[string]string{"q": text}
+	if l.apiKey != "" {
+		body["api_key"] = l.apiKey
+	}
+
+	var detections []LibreDetection
+	if err := l.doJSON(ctx, "POST", "detect", body, &detections); err != nil {
+		return nil, err
+	}
+
+	return detections, nil
+}
+
+// Fetches the languages supported by the server.
+func (l *LibreTranslator) Languages(ctx context.Context) ([]LibreLanguage, error) {
+	var languages []LibreLanguage
+	if err := l.doJSON(ctx, "GET", "languages", nil, &languages); err != nil {
+		return nil, err
+	}
+
+	return languages, nil
+}
+
+func (l *LibreTranslator) request(q interface{}) libreRequest {
+	return libreRequest{
+		Q:      q,
+		Source: l.source,
+		Target: l.target,
+		Format: l.format,
+		APIKey: l.apiKey,
+	}
+}
+
+// Sends a request to the server, the body being marshaled to JSON, and decodes the response into out.
+func (l *LibreTranslator) doJSON(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, l.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := l.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Sends the request, returning unsuccessful statuses as *StatusError with the error message of the server.
+func (l *LibreTranslator) send(req *http.Request) (*http.Response, error) {
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var response struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		return nil, newStatusError(resp, response.Error)
+	}
+
+	return resp, nil
 }
@@ -0,0 +1,143 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Starts a fake LibreTranslate server upper-casing the texts and requiring the key "key".
+func newFakeLibre(tb testing.TB, requests *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		if r.URL.Path == "/languages" {
+			w.Write([]byte(`[{"code":"en","name":"English","targets":["fr","de"]}]`))
+			return
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid JSON"}`))
+			return
+		}
+		if body["api_key"] != "key" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Invalid API key"}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/detect":
+			w.Write([]byte(`[{"confidence":92.0,"language":"fr"},{"confidence":4.0,"language":"it"}]`))
+		case "/translate":
+			var translations []string
+			var detected []map[string]interface{}
+			for _, q := range body["q"].([]interface{}) {
+				translations = append(translations, strings.ToUpper(q.(string)))
+				detected = append(detected, map[string]interface{}{"language": "en", "confidence": 90.0})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"translatedText": translations, "detectedLanguage": detected})
+		}
+	}))
+	tb.Cleanup(server.Close)
+	return server
+}
+
+func TestLibreTranslateBatch(t *testing.T) {
+	requests := 0
+	l := NewLibreTranslator("auto", "fr", nil)
+	l.SetBaseURL(newFakeLibre(t, &requests).URL + "/")
+	l.SetAPIKey("key")
+
+	texts := []string{`say "hi"`, "two\nlines", "back\\slash"}
+	translations, err := l.TranslateDetailed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i, translation := range translations {
+		if translation.Text != strings.ToUpper(texts[i]) {
+			t.Fatalf("expected %q, got %q", strings.ToUpper(texts[i]), translation.Text)
+		}
+		if translation.DetectedLanguage == nil || translation.DetectedLanguage.Language != "en" {
+			t.Fatalf("expected the detected language, got %+v", translation.DetectedLanguage)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected a single request, got %d", requests)
+	}
+}
+
+func TestLibreDetectAndLanguages(t *testing.T) {
+	requests := 0
+	l := NewLibreTranslator("auto", "fr", nil)
+	l.SetBaseURL(newFakeLibre(t, &requests).URL)
+	l.SetAPIKey("key")
+
+	detections, err := l.Detect(context.Background(), "Bonjour")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if detections[0].Language != "fr" || detections[0].Confidence != 92 {
+		t.Fatalf("unexpected detections %+v", detections)
+	}
+
+	languages, err := l.Languages(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(languages) != 1 || languages[0].Code != "en" || len(languages[0].Targets) != 2 {
+		t.Fatalf("unexpected languages %+v", languages)
+	}
+}
+
+func TestLibreInvalidAPIKey(t *testing.T) {
+	requests := 0
+	l := NewLibreTranslator("en", "fr", nil)
+	l.SetBaseURL(newFakeLibre(t, &requests).URL)
+	l.SetAPIKey("wrong")
+
+	_, err := l.Translate("hello")
+	if !errors.Is(err, errs.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Invalid API key") {
+		t.Fatalf("expected the server message, got %v", err)
+	}
+}
+
+func TestLibreAlternatives(t *testing.T) {
+	var requested interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requested = body["alternatives"]
+		// the main translation repeated among the alternatives is kept once, and the
+		// candidates past the requested number are dropped
+		w.Write([]byte(`{"translatedText":"Bonjour","alternatives":["Salut","Bonjour","Coucou","Allô"]}`))
+	}))
+	defer server.Close()
+
+	l := NewLibreTranslator("en", "fr", nil)
+	l.SetBaseURL(server.URL)
+
+	alternatives, err := l.Alternatives(context.Background(), "hello", 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if requested != 2.0 {
+		t.Fatalf("expected 2 alternatives besides the translation to be requested, got %v", requested)
+	}
+	if len(alternatives) != 3 || alternatives[0].Text != "Bonjour" || alternatives[1].Text != "Salut" || alternatives[2].Text != "Coucou" {
+		t.Fatalf("unexpected alternatives %+v", alternatives)
+	}
+}
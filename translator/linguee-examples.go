@@ -0,0 +1,127 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kashari/go-translate/bread"
+)
+
+// Most pages of external sources fetched by a single Examples call.
+const lingueeMaxExamplePages = 10
+
+// An example sentence pair found by Linguee on a bilingual website.
+type LingueeExample struct {
+	Source string
+	Target string
+	// Domain of the website the sentences come from, such as europarl.europa.eu.
+	Domain string
+}
+
+// How a translation of a term is used in the examples from external sources.
+type TermCheck struct {
+	Term        string
+	Translation string
+	// Number of examples found for the term.
+	Total int
+	// The examples whose target sentence contains the translation.
+	Matching []LingueeExample
+}
+
+// Returns the share of the examples using the translation, from 0 to 1.
+func (c TermCheck) Share() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(len(c.Matching)) / float64(c.Total)
+}
+
+// Returns up to limit example sentence pairs containing the phrase, taken from the external
+// sources section of Linguee. Further pages are fetched until the limit is reached or no more
+// examples are found, limit <= 0 returning the examples of the first page.
+func (lt *LingueeTranslator) Examples(ctx context.Context, phrase string, limit int) ([]LingueeExample, error) {
+	if !isInputValid(phrase, 100) {
+		return nil, fmt.Errorf("invalid input phrase")
+	}
+
+	var examples []LingueeExample
+	seen := make(map[LingueeExample]bool)
+	for page := 1; page <= lingueeMaxExamplePages; page++ {
+		root, err := lt.fetch(ctx, phrase, page)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, example := range parseLingueeExamples(root) {
+			if seen[example] {
+				continue
+			}
+			seen[example] = true
+			examples = append(examples, example)
+			added++
+		}
+
+		if limit <= 0 {
+			return examples, nil
+		}
+		if len(examples) >= limit {
+			return examples[:limit], nil
+		}
+		// Linguee serves the last page again past the end
+		if added == 0 {
+			break
+		}
+	}
+
+	return examples, nil
+}
+
+// Checks how the translation of a term is used in up to limit examples from external sources,
+// to see whether it is the established one. The comparison ignores case.
+func (lt *LingueeTranslator) CheckTerm(ctx context.Context, term, translation string, limit int) (*TermCheck, error) {
+	examples, err := lt.Examples(ctx, term, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &TermCheck{Term: term, Translation: translation, Total: len(examples)}
+	for _, example := range examples {
+		if strings.Contains(strings.ToLower(example.Target), strings.ToLower(translation)) {
+			check.Matching = append(check.Matching, example)
+		}
+	}
+
+	return check, nil
+}
+
+func parseLingueeExamples(root bread.Root) []LingueeExample {
+	table, ok := findElement(root, "table", "class", "result_table")
+	if !ok {
+		return nil
+	}
+
+	var examples []LingueeExample
+	for _, row := range table.FindAll("tr") {
+		left, ok := findElement(row, "td", "class", "left")
+		if !ok {
+			continue
+		}
+		right, ok := findElement(row, "td", "class", "right2")
+		if !ok {
+			continue
+		}
+
+		example := LingueeExample{
+			Source: elementText(left, "div", "class", "wrap"),
+			Target: elementText(right, "div", "class", "wrap"),
+			Domain: elementText(left, "div", "class", "source_url"),
+		}
+		if example.Source != "" && example.Target != "" {
+			examples = append(examples, example)
+		}
+	}
+
+	return examples
+}
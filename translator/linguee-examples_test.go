@@ -0,0 +1,87 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Serves two pages of examples, repeating the last one past the end like Linguee does.
+func newFakeLingueeExamples(t *testing.T, requests *int) *httptest.Server {
+	pages := make([][]byte, 2)
+	for i := range pages {
+		page, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("linguee-examples-%d.html", i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages[i] = page
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Query().Get("query") != "house arrest" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Write(pages[0])
+			return
+		}
+		w.Write(pages[1])
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLingueeExamples(t *testing.T) {
+	requests := 0
+	l := NewLingueeTranslator("english", "german", nil)
+	l.baseURL = newFakeLingueeExamples(t, &requests).URL + "/"
+
+	examples, err := l.Examples(context.Background(), "house arrest", 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := LingueeExample{
+		Source: "The opposition leader remains under house arrest.",
+		Target: "Der Oppositionsführer steht weiterhin unter Hausarrest.",
+		Domain: "europarl.europa.eu",
+	}
+	if len(examples) != 2 || examples[0] != expected {
+		t.Fatalf("expected the examples of the first page, got %+v", examples)
+	}
+
+	requests = 0
+	examples, err = l.Examples(context.Background(), "house arrest", 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(examples) != 3 || examples[2].Domain != "un.org" || requests != 2 {
+		t.Fatalf("expected 3 examples from 2 pages, got %d from %d: %+v", len(examples), requests, examples)
+	}
+
+	// stops when a page brings nothing new
+	requests = 0
+	examples, _ = l.Examples(context.Background(), "house arrest", 50)
+	if len(examples) != 4 || requests != 3 {
+		t.Fatalf("expected 4 examples from 3 pages, got %d from %d", len(examples), requests)
+	}
+}
+
+func TestLingueeCheckTerm(t *testing.T) {
+	requests := 0
+	l := NewLingueeTranslator("english", "german", nil)
+	l.baseURL = newFakeLingueeExamples(t, &requests).URL + "/"
+
+	check, err := l.CheckTerm(context.Background(), "house arrest", "hausarrest", 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if check.Total != 4 || len(check.Matching) != 3 || check.Share() != 0.75 {
+		t.Fatalf("unexpected term check %+v", check)
+	}
+}
@@ -1,23 +1,27 @@
 package translator
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/kashari/go-translate/bread"
 	"github.com/kashari/go-translate/constants"
 	errs "github.com/kashari/go-translate/errors"
+	"golang.org/x/net/html/charset"
 )
 
+// Matches the quoted arguments of the playSound calls of the audio links.
+var lingueeSoundArgument = regexp.MustCompile(`"([^"]*)"`)
+
 type LingueeTranslator struct {
 	baseURL            string
 	source             string
 	target             string
-	elementTag         string
-	elementQuery       map[string]string
 	payloadKey         string
 	proxies            *url.URL
 	urlParams          url.Values
@@ -27,18 +31,14 @@ type LingueeTranslator struct {
 
 func NewLingueeTranslator(source, target string, proxies *url.URL) *LingueeTranslator {
 	return &LingueeTranslator{
-		baseURL:    constants.BASE_URLS["LINGUEE"],
-		source:     source,
-		target:     target,
-		elementTag: "a",
-		elementQuery: map[string]string{
-			"class": "dictLink featured",
-		},
+		baseURL:            constants.BASE_URLS["LINGUEE"],
+		source:             source,
+		target:             target,
 		proxies:            proxies,
 		payloadKey:         "source",
 		urlParams:          url.Values{},
 		supportedLanguages: constants.LINGUEE_LANGUAGES_TO_CODES,
-		client:             &http.Client{},
+		client:             newHTTPClient(proxies),
 	}
 }
 
@@ -46,60 +46,229 @@ func (bt *LingueeTranslator) SameSourceTarget() bool {
 	return bt.source == bt.target
 }
 
-func (lt *LingueeTranslator) Translate(word string) (interface{}, error) {
+// Returns the featured translations of the word.
+func (lt *LingueeTranslator) Translate(word string) ([]string, error) {
 	if lt.SameSourceTarget() || isEmpty(word) {
-		return word, nil
+		return []string{word}, nil
 	}
 
-	if isInputValid(word, 50) {
-		url := fmt.Sprintf("%s%s-%s/search/?source=%s&query=%s", lt.baseURL, lt.source, lt.target, lt.source, url.QueryEscape(word))
+	entries, err := lt.Lookup(context.Background(), word)
+	if err != nil {
+		return nil, err
+	}
 
-		response, err := bread.GetWithClient(url, lt.client)
-		if err != nil {
-			return nil, err
+	var translations []string
+	for _, entry := range entries {
+		for _, translation := range entry.Translations {
+			if translation.Featured {
+				translations = append(translations, translation.Text)
+			}
 		}
+	}
+
+	if len(translations) == 0 {
+		return nil, errs.ErrTranslationNotFound
+	}
+
+	return translations, nil
+}
 
-		statusCode := 200
-		if statusCode == 429 {
-			errs.TooManyRequestsError()
-			return nil, nil
+// Returns up to n dictionary translations of the word in the order Linguee lists them.
+// Linguee does not score its results.
+func (lt *LingueeTranslator) Alternatives(ctx context.Context, word string, n int) ([]Alternative, error) {
+	entries, err := lt.Lookup(ctx, word)
+	if err != nil {
+		return nil, err
+	}
+
+	var alternatives []Alternative
+	for _, entry := range entries {
+		for _, translation := range entry.Translations {
+			alternatives = appendAlternative(alternatives, translation.Text, 0)
 		}
+	}
+
+	if len(alternatives) == 0 {
+		return nil, errs.ErrTranslationNotFound
+	}
+
+	return limitAlternatives(alternatives, n), nil
+}
+
+// Looks the word up in the dictionary and returns the entries matching it exactly, or the
+// closest ones when there is no exact match.
+func (lt *LingueeTranslator) Lookup(ctx context.Context, word string) ([]DictionaryEntry, error) {
+	if !isInputValid(word, 50) {
+		return nil, fmt.Errorf("invalid input word")
+	}
+
+	root, err := lt.fetch(ctx, word, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return lt.parseEntries(root), nil
+}
+
+// Fetches and parses the Linguee page of the word, pages after the first one only
+// listing more examples from external sources.
+func (lt *LingueeTranslator) fetch(ctx context.Context, word string, page int) (bread.Root, error) {
+	url := fmt.Sprintf("%s%s-%s/search/?source=%s&query=%s", lt.baseURL, lt.source, lt.target, lt.source, url.QueryEscape(word))
+	if page > 1 {
+		url += fmt.Sprintf("&page=%d", page)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return bread.Root{}, err
+	}
+
+	resp, err := lt.client.Do(req)
+	if err != nil {
+		return bread.Root{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return bread.Root{}, newStatusError(resp, "")
+	}
+
+	body, err := charset.NewReader(resp.Body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return bread.Root{}, err
+	}
+	html, err := io.ReadAll(body)
+	if err != nil {
+		return bread.Root{}, err
+	}
 
-		root := bread.HTMLParse(response)
-		if root.Error != nil {
-			return nil, root.Error
+	root := bread.HTMLParse(string(html))
+	return root, root.Error
+}
+
+func (lt *LingueeTranslator) parseEntries(root bread.Root) []DictionaryEntry {
+	section, ok := findElement(root, "div", "class", "exact")
+	if !ok {
+		section = root
+	}
+
+	var entries []DictionaryEntry
+	for _, lemma := range section.FindAll("div", "class", "lemma") {
+		desc, ok := findElement(lemma, "h2", "class", "lemma_desc")
+		if !ok {
+			continue
 		}
 
-		log.Println(root)
+		entry := DictionaryEntry{
+			Lemma:     elementText(desc, "a", "class", "dictLink"),
+			Featured:  hasClass(lemma, "featured"),
+			Frequency: strings.Trim(elementText(desc, "span", "class", "tag_c"), "()"),
+			Audio:     lt.audioLinks(desc),
+		}
+		if wordType, ok := findElement(desc, "span", "class", "tag_wordtype"); ok {
+			entry.PartOfSpeech, entry.Gender = splitPartOfSpeech(grammarDescription(wordType))
+		}
 
-		var elements []bread.Root
-		for key, value := range lt.elementQuery {
-			elements = root.FindAll(lt.elementTag, key, value)
+		// the translations of these groups are listed as less common
+		var lessCommon []bread.Root
+		for _, group := range lemma.FindAll("div", "class", "translation_group") {
+			lessCommon = append(lessCommon, group.FindAll("div", "class", "translation")...)
 		}
 
-		if len(elements) == 0 {
-			errs.TranslationNotFoundError()
-			return nil, nil
+		for _, node := range lemma.FindAll("div", "class", "translation") {
+			translation := DictionaryTranslation{
+				Text:           elementText(node, "a", "class", "dictLink"),
+				Featured:       hasClass(node, "featured"),
+				UsageFrequency: strings.Trim(elementText(node, "span", "class", "tag_c"), "()"),
+				Audio:          lt.audioLinks(node),
+			}
+			if translation.UsageFrequency == "" && containsNode(lessCommon, node) {
+				translation.UsageFrequency = "less common"
+			}
+			if tagType, ok := findElement(node, "span", "class", "tag_type"); ok {
+				translation.PartOfSpeech, translation.Gender = splitPartOfSpeech(grammarDescription(tagType))
+			}
+			for _, example := range node.FindAll("div", "class", "example") {
+				translation.Examples = append(translation.Examples, ExamplePair{
+					Source: elementText(example, "span", "class", "tag_s"),
+					Target: elementText(example, "span", "class", "tag_t"),
+				})
+			}
+			entry.Translations = append(entry.Translations, translation)
 		}
 
-		var filteredElements []string
-		for _, el := range elements {
-			pronounElement := el.Find("span", "class", "placeholder")
-			var pronoun string
-			if pronounElement.Error == nil {
-				pronoun = pronounElement.Text()
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Returns the recordings of the audio links of the element, whose onclick attribute looks like
+// playSound(this,"EN_US/6b/6b3e4b5ef1c2","English (USA)","EN_UK/a1/a1f0c2d3e4b5","English (UK)").
+func (lt *LingueeTranslator) audioLinks(r bread.Root) []AudioLink {
+	var links []AudioLink
+	for _, audio := range r.FindAll("a", "class", "audio") {
+		arguments := lingueeSoundArgument.FindAllStringSubmatch(audio.Attrs()["onclick"], -1)
+		for i := 0; i+1 < len(arguments); i += 2 {
+			path := arguments[i][1]
+			if !strings.HasSuffix(path, ".mp3") {
+				path += ".mp3"
 			}
-			filteredElements = append(filteredElements, strings.ReplaceAll(el.Text(), pronoun, ""))
+			links = append(links, AudioLink{URL: lt.baseURL + "mp3/" + path, Language: arguments[i+1][1]})
 		}
+	}
+	return links
+}
 
-		if len(filteredElements) == 0 {
-			errs.TranslationNotFoundError()
-			return nil, nil
+// Returns the full grammatical description of a word type tag, "noun, neuter" for an "nt" tag.
+func grammarDescription(r bread.Root) string {
+	if title := r.Attrs()["title"]; title != "" {
+		return title
+	}
+	return normalizeSpace(r.FullText())
+}
+
+// Finds the first matching element, unlike bread.Root.Find it is safe to call on missing elements.
+func findElement(r bread.Root, args ...string) (bread.Root, bool) {
+	if r.Pointer == nil {
+		return r, false
+	}
+	found := r.Find(args...)
+	return found, found.Error == nil
+}
+
+// Returns the whitespace normalized text of the first matching element, empty when there is none.
+func elementText(r bread.Root, args ...string) string {
+	found, ok := findElement(r, args...)
+	if !ok {
+		return ""
+	}
+	return normalizeSpace(found.FullText())
+}
+
+// Reports whether the element has any of the classes.
+func hasClass(r bread.Root, classes ...string) bool {
+	for _, value := range strings.Fields(r.Attrs()["class"]) {
+		for _, class := range classes {
+			if value == class {
+				return true
+			}
 		}
+	}
+	return false
+}
 
-		return filteredElements, nil
+func containsNode(nodes []bread.Root, node bread.Root) bool {
+	for _, n := range nodes {
+		if n.Pointer == node.Pointer {
+			return true
+		}
 	}
-	return nil, fmt.Errorf("invalid input word")
+	return false
+}
+
+func normalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
 }
 
 func isInputValid(word string, maxChars int) bool {
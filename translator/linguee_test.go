@@ -0,0 +1,131 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Serves the fixture as the Linguee search page, checking the query of the request.
+func newFakeLinguee(t *testing.T, fixture string) *httptest.Server {
+	page, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/english-german/search/" || r.URL.Query().Get("source") != "english" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLingueeLookup(t *testing.T) {
+	l := NewLingueeTranslator("english", "german", nil)
+	l.baseURL = newFakeLinguee(t, "linguee-house.html").URL + "/"
+
+	var dictionary Dictionary = l
+	entries, err := dictionary.Lookup(context.Background(), "house")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// the inexact matches are left out
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	noun := entries[0]
+	if noun.Lemma != "house" || noun.PartOfSpeech != "noun" || !noun.Featured || noun.Frequency != "" {
+		t.Fatalf("unexpected noun entry %+v", noun)
+	}
+	if len(noun.Audio) != 2 || noun.Audio[1].URL != l.baseURL+"mp3/EN_UK/a1/a1f0c2d3e4b5.mp3" || noun.Audio[1].Language != "English (UK)" {
+		t.Fatalf("unexpected audio links %+v", noun.Audio)
+	}
+
+	if len(noun.Translations) != 3 {
+		t.Fatalf("expected 3 translations, got %+v", noun.Translations)
+	}
+	haus := noun.Translations[0]
+	if haus.Text != "Haus" || !haus.Featured || haus.PartOfSpeech != "noun" || haus.Gender != "neuter" || haus.UsageFrequency != "often used" {
+		t.Fatalf("unexpected translation %+v", haus)
+	}
+	if len(haus.Examples) != 2 || haus.Examples[1] != (ExamplePair{Source: "The house was empty.", Target: "Das Haus war leer."}) {
+		t.Fatalf("unexpected examples %+v", haus.Examples)
+	}
+	if len(haus.Audio) != 1 || haus.Audio[0].Language != "Deutsch" {
+		t.Fatalf("unexpected translation audio %+v", haus.Audio)
+	}
+	if heim := noun.Translations[2]; heim.Text != "Heim" || heim.Featured || heim.UsageFrequency != "less common" {
+		t.Fatalf("unexpected less common translation %+v", heim)
+	}
+
+	verb := entries[1]
+	if verb.PartOfSpeech != "verb" || verb.Featured || verb.Frequency != "rarely used" || verb.Translations[0].Text != "unterbringen jdn." {
+		t.Fatalf("unexpected verb entry %+v", verb)
+	}
+
+	translations, err := l.Translate("house")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(translations) != 2 || translations[0] != "Haus" || translations[1] != "Gebäude" {
+		t.Fatalf("expected the featured translations, got %v", translations)
+	}
+}
+
+func TestLingueeTooManyRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	l := NewLingueeTranslator("english", "german", nil)
+	l.baseURL = server.URL + "/"
+
+	if _, err := l.Lookup(context.Background(), "house"); !errors.Is(err, errs.ErrTooManyRequests) {
+		t.Fatalf("expected ErrTooManyRequests, got %v", err)
+	}
+}
+
+func TestLingueeAlternatives(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("testdata", "linguee-house.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the same translation listed twice is kept once
+	page = bytes.Replace(page, []byte(">Heim<"), []byte(">Haus<"), 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	}))
+	defer server.Close()
+
+	l := NewLingueeTranslator("english", "german", nil)
+	l.baseURL = server.URL + "/"
+
+	alternatives, err := l.Alternatives(context.Background(), "house", 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(alternatives) != 3 || alternatives[0].Text != "Haus" || alternatives[1].Text != "Gebäude" || alternatives[2].Text != "unterbringen jdn." {
+		t.Fatalf("unexpected alternatives %+v", alternatives)
+	}
+
+	alternatives, err = l.Alternatives(context.Background(), "house", 2)
+	if err != nil || len(alternatives) != 2 {
+		t.Fatalf("expected 2 alternatives, got %+v %v", alternatives, err)
+	}
+}
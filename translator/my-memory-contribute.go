@@ -0,0 +1,210 @@
+package translator
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A source segment and its approved translation.
+type MyMemoryPair struct {
+	Source      string
+	Translation string
+}
+
+// Sets the subject, or domain, attached to the contributed translations, such as "Legal".
+func (m *MyMemoryTranslator) SetSubject(subject string) {
+	m.subject = subject
+}
+
+// Stores a translation of the source segment in the translation memory, the private one
+// of the key when set, so that it is used by the following lookups.
+func (m *MyMemoryTranslator) Contribute(ctx context.Context, source, translation string) error {
+	if strings.TrimSpace(source) == "" || strings.TrimSpace(translation) == "" {
+		return fmt.Errorf("source and translation are required")
+	}
+	if m.source == "auto" {
+		return fmt.Errorf("the source language is required to contribute translations")
+	}
+
+	params := m.params()
+	params.Del("mt")
+	params.Set("seg", source)
+	params.Set("tra", translation)
+	if m.subject != "" {
+		params.Set("subject", m.subject)
+	}
+
+	var response struct {
+		ResponseStatus  myMemoryNumber `json:"responseStatus"`
+		ResponseDetails interface{}    `json:"responseDetails"`
+	}
+	if err := m.doJSON(ctx, "set", params, &response); err != nil {
+		return err
+	}
+
+	if status := int(response.ResponseStatus); status != 0 && status != 200 {
+		return &StatusError{StatusCode: status, Message: fmt.Sprint(response.ResponseDetails)}
+	}
+	return nil
+}
+
+// Contributes every pair of the TMX or CSV file, see LoadTranslationPairs, and returns how
+// many were stored. It stops at the first failure.
+func (m *MyMemoryTranslator) ImportFile(ctx context.Context, path string) (int, error) {
+	pairs, err := LoadTranslationPairs(path, m.source, m.target)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, pair := range pairs {
+		if err := m.Contribute(ctx, pair.Source, pair.Translation); err != nil {
+			return i, fmt.Errorf("failed to contribute %q: %w", pair.Source, err)
+		}
+	}
+
+	return len(pairs), nil
+}
+
+// Reads the translation pairs of a TMX or CSV file. CSV files have a source and a translation
+// column, TMX units are read in the source and target languages, en matching en-GB for example.
+func LoadTranslationPairs(path, source, target string) ([]MyMemoryPair, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readCSVPairs(file)
+	case ".tmx":
+		return readTMXPairs(file, source, target)
+	default:
+		return nil, fmt.Errorf("unsupported translation memory file %s, expected .tmx or .csv", path)
+	}
+}
+
+func readCSVPairs(r io.Reader) ([]MyMemoryPair, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []MyMemoryPair
+	for _, record := range records {
+		source, translation := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if source == "" || translation == "" {
+			continue
+		}
+		pairs = append(pairs, MyMemoryPair{Source: source, Translation: translation})
+	}
+
+	return pairs, nil
+}
+
+func readTMXPairs(r io.Reader, source, target string) ([]MyMemoryPair, error) {
+	var tmx struct {
+		Header struct {
+			SourceLanguage string `xml:"srclang,attr"`
+		} `xml:"header"`
+		Units []struct {
+			Variants []struct {
+				Language string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+				// TMX 1.1 used a plain lang attribute
+				LegacyLanguage string `xml:"lang,attr"`
+				Segment        struct {
+					Text string `xml:",innerxml"`
+				} `xml:"seg"`
+			} `xml:"tuv"`
+		} `xml:"body>tu"`
+	}
+	if err := xml.NewDecoder(r).Decode(&tmx); err != nil {
+		return nil, err
+	}
+
+	if source == "" || source == "auto" {
+		source = tmx.Header.SourceLanguage
+	}
+
+	var pairs []MyMemoryPair
+	for _, unit := range tmx.Units {
+		var pair MyMemoryPair
+		for _, variant := range unit.Variants {
+			language := variant.Language
+			if language == "" {
+				language = variant.LegacyLanguage
+			}
+			text := strings.TrimSpace(tmxText(variant.Segment.Text))
+			switch {
+			case sameLanguage(language, source):
+				pair.Source = text
+			case sameLanguage(language, target):
+				pair.Translation = text
+			}
+		}
+		if pair.Source != "" && pair.Translation != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return pairs, nil
+}
+
+// Returns the text of a segment without its inline markup. The native code held by bpt, ept,
+// it, ph and ut elements is left out, the text of hi elements and of sub flows is kept.
+func tmxText(inner string) string {
+	decoder := xml.NewDecoder(strings.NewReader("<seg>" + inner + "</seg>"))
+	var builder strings.Builder
+	// whether the text of every open element is kept, innermost last
+	keep := []bool{true}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return builder.String()
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "bpt", "ept", "it", "ph", "ut":
+				keep = append(keep, false)
+			case "sub":
+				keep = append(keep, true)
+			default:
+				keep = append(keep, keep[len(keep)-1])
+			}
+		case xml.EndElement:
+			keep = keep[:len(keep)-1]
+		case xml.CharData:
+			if keep[len(keep)-1] {
+				builder.Write(t)
+			}
+		}
+	}
+}
+
+// Reports whether the languages match, a language without region matching all its regions.
+func sameLanguage(language, other string) bool {
+	if language == "" || other == "" {
+		return false
+	}
+	if strings.EqualFold(language, other) {
+		return true
+	}
+	base := func(code string) string {
+		return strings.ToLower(strings.SplitN(code, "-", 2)[0])
+	}
+	if strings.Contains(language, "-") && strings.Contains(other, "-") {
+		return false
+	}
+	return base(language) == base(other)
+}
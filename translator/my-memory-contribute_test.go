@@ -0,0 +1,95 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header srclang="en-GB" datatype="plaintext" segtype="sentence" adminlang="en" o-tmf="none" creationtool="test" creationtoolversion="1"/>
+  <body>
+    <tu>
+      <tuv xml:lang="en-GB"><seg>Save &amp; close</seg></tuv>
+      <tuv xml:lang="fr-FR"><seg>Enregistrer et fermer</seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en-GB"><seg>Click <bpt i="1">&lt;b&gt;</bpt>here<ept i="1">&lt;/b&gt;</ept></seg></tuv>
+      <tuv xml:lang="fr-FR"><seg>Cliquez <bpt i="1">&lt;b&gt;</bpt>ici<ept i="1">&lt;/b&gt;</ept></seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en-GB"><seg>Press <hi type="b">Save</hi> now<ph x="1">&lt;br/&gt;</ph></seg></tuv>
+      <tuv xml:lang="fr-FR"><seg>Appuyez sur <hi type="b">Enregistrer</hi> maintenant<ph x="1">&lt;br/&gt;</ph></seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="en-GB"><seg>Untranslated</seg></tuv>
+      <tuv xml:lang="de-DE"><seg>Unübersetzt</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+func TestLoadTranslationPairs(t *testing.T) {
+	dir := t.TempDir()
+	tmx := filepath.Join(dir, "memory.tmx")
+	csv := filepath.Join(dir, "memory.csv")
+	os.WriteFile(tmx, []byte(sampleTMX), 0o644)
+	os.WriteFile(csv, []byte("Hello,Bonjour\n\"Yes, please\",\"Oui, s'il vous plaît\"\n"), 0o644)
+
+	pairs, err := LoadTranslationPairs(tmx, "en", "fr")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []MyMemoryPair{
+		{Source: "Save & close", Translation: "Enregistrer et fermer"},
+		{Source: "Click here", Translation: "Cliquez ici"},
+		// the text of hi elements is translatable, the native code of ph is not
+		{Source: "Press Save now", Translation: "Appuyez sur Enregistrer maintenant"},
+	}
+	if len(pairs) != len(expected) || pairs[0] != expected[0] || pairs[1] != expected[1] || pairs[2] != expected[2] {
+		t.Fatalf("expected %+v, got %+v", expected, pairs)
+	}
+
+	pairs, err = LoadTranslationPairs(csv, "en", "fr")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pairs) != 2 || pairs[1].Translation != "Oui, s'il vous plaît" {
+		t.Fatalf("unexpected CSV pairs %+v", pairs)
+	}
+}
+
+func TestMyMemoryImportFile(t *testing.T) {
+	var contributed []MyMemoryPair
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/set" || q.Get("key") != "private" || q.Get("de") != "me@example.com" ||
+			q.Get("subject") != "Software" || q.Get("langpair") != "en|fr" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		contributed = append(contributed, MyMemoryPair{Source: q.Get("seg"), Translation: q.Get("tra")})
+		w.Write([]byte(`{"responseData":"OK","responseStatus":200,"responseDetails":[445567]}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "memory.tmx")
+	os.WriteFile(path, []byte(sampleTMX), 0o644)
+
+	m := NewMyMemoryTranslator("en", "fr", nil)
+	m.SetBaseURL(server.URL)
+	m.SetKey("private")
+	m.SetEmail("me@example.com")
+	m.SetSubject("Software")
+
+	imported, err := m.ImportFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if imported != 3 || len(contributed) != 3 || contributed[0].Source != "Save & close" {
+		t.Fatalf("unexpected contributions %d %+v", imported, contributed)
+	}
+}
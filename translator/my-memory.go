@@ -1,58 +1,311 @@
 package translator
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/kashari/go-translate/constants"
+	errs "github.com/kashari/go-translate/errors"
+)
+
+const (
+	// Longest query accepted by MyMemory, in bytes.
+	myMemoryMaxQuery = 500
+	// Requests sent at the same time by TranslateBatch.
+	myMemoryConcurrency = 4
 )
 
 type MyMemoryTranslator struct {
-	baseURL string
-	source  string
-	target  string
-	proxies *url.URL
-	client  *http.Client
+	baseURL            string
+	source             string
+	target             string
+	proxies            *url.URL
+	client             *http.Client
+	supportedLanguages map[string]string
+	email              string
+	key                string
+	subject            string
+	disableMT          bool
+}
+
+// The translation chosen by MyMemory together with every candidate it considered.
+type MyMemoryResult struct {
+	Text string
+	// Similarity between the text and the segment of the chosen translation, from 0 to 1.
+	Match   float64
+	Matches []MyMemoryMatch
+}
+
+// A candidate translation from the translation memory or the machine translation engine.
+type MyMemoryMatch struct {
+	ID          string
+	Segment     string
+	Translation string
+	Source      string
+	Target      string
+	Subject     string
+	Reference   string
+	CreatedBy   string
+	UsageCount  int
+	// Similarity between the text and the segment, from 0 to 1.
+	Match float64
+	// Quality of the translation, from 0 to 100.
+	Quality int
+}
+
+// Reports whether the match comes from machine translation instead of a human contribution.
+func (m MyMemoryMatch) Machine() bool {
+	return m.CreatedBy == "MT!"
+}
+
+// Decodes the numbers MyMemory sometimes sends as strings.
+type myMemoryNumber float64
+
+func (n *myMemoryNumber) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*n = myMemoryNumber(f)
+	return nil
 }
 
 func NewMyMemoryTranslator(source, target string, proxies *url.URL) *MyMemoryTranslator {
 	return &MyMemoryTranslator{
-		baseURL: constants.BASE_URLS["MYMEMORY"],
-		source:  source,
-		target:  target,
-		proxies: proxies,
-		client:  &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxies)}},
+		baseURL:            strings.TrimSuffix(constants.BASE_URLS["MYMEMORY"], "get"),
+		source:             source,
+		target:             target,
+		proxies:            proxies,
+		client:             newHTTPClient(proxies),
+		supportedLanguages: constants.MY_MEMORY_LANGUAGES_TO_CODES,
 	}
 }
 
+// Sets the root URL of the API, mostly useful to point the translator to a stand-in server.
+func (m *MyMemoryTranslator) SetBaseURL(baseURL string) {
+	m.baseURL = strings.TrimRight(baseURL, "/") + "/"
+}
+
+// Sets the contact email sent as the de parameter, which raises the free daily quota.
+func (m *MyMemoryTranslator) SetEmail(email string) {
+	m.email = email
+}
+
+// Sets the key of a private translation memory, whose entries are then used for the translations.
+func (m *MyMemoryTranslator) SetKey(key string) {
+	m.key = key
+}
+
+// Enables or disables the machine translation fallback, enabled by default.
+// When disabled only the translation memory is searched.
+func (m *MyMemoryTranslator) SetMachineTranslation(enabled bool) {
+	m.disableMT = !enabled
+}
+
 func (m *MyMemoryTranslator) Translate(text string) (string, error) {
-	url := m.baseURL + "?langpair=" + m.source + "|" + m.target + "&q=" + url.QueryEscape(text)
-	req, err := http.NewRequest("GET", url, nil)
+	result, err := m.TranslateDetailed(context.Background(), text)
 	if err != nil {
-		log.Panic(err)
+		return "", err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return result.Text, nil
+}
 
-	resp, err := m.client.Do(req)
-	if err != nil {
-		log.Panic(err)
+// Translates the text and returns the chosen translation with all the matches MyMemory found.
+func (m *MyMemoryTranslator) TranslateDetailed(ctx context.Context, text string) (*MyMemoryResult, error) {
+	if len(text) > myMemoryMaxQuery {
+		return nil, fmt.Errorf("text is longer than %d bytes: %w", myMemoryMaxQuery, errs.ErrTooLongText)
 	}
 
-	defer resp.Body.Close()
+	params := m.params()
+	params.Set("q", text)
 
 	var response struct {
 		ResponseData struct {
-			TranslatedText string `json:"translatedText"`
+			TranslatedText string         `json:"translatedText"`
+			Match          myMemoryNumber `json:"match"`
 		} `json:"responseData"`
+		ResponseStatus  myMemoryNumber `json:"responseStatus"`
+		ResponseDetails string         `json:"responseDetails"`
+		QuotaFinished   bool           `json:"quotaFinished"`
+		Matches         []struct {
+			ID          json.RawMessage `json:"id"`
+			Segment     string          `json:"segment"`
+			Translation string          `json:"translation"`
+			Source      string          `json:"source"`
+			Target      string          `json:"target"`
+			Subject     json.RawMessage `json:"subject"`
+			Reference   string          `json:"reference"`
+			CreatedBy   string          `json:"created-by"`
+			UsageCount  myMemoryNumber  `json:"usage-count"`
+			Match       myMemoryNumber  `json:"match"`
+			Quality     myMemoryNumber  `json:"quality"`
+		} `json:"matches"`
+	}
+
+	if err := m.doJSON(ctx, "get", params, &response); err != nil {
+		return nil, err
+	}
+
+	if response.QuotaFinished {
+		return nil, fmt.Errorf("%s: %w", response.ResponseDetails, errs.ErrQuotaExceeded)
+	}
+	if status := int(response.ResponseStatus); status != 0 && status != http.StatusOK {
+		return nil, &StatusError{StatusCode: status, Message: response.ResponseDetails}
+	}
+
+	result := &MyMemoryResult{
+		Text:  response.ResponseData.TranslatedText,
+		Match: float64(response.ResponseData.Match),
+	}
+	for _, match := range response.Matches {
+		result.Matches = append(result.Matches, MyMemoryMatch{
+			ID:          strings.Trim(string(match.ID), `"`),
+			Segment:     match.Segment,
+			Translation: match.Translation,
+			Source:      match.Source,
+			Target:      match.Target,
+			Subject:     myMemoryString(match.Subject),
+			Reference:   match.Reference,
+			CreatedBy:   match.CreatedBy,
+			UsageCount:  int(match.UsageCount),
+			Match:       float64(match.Match),
+			Quality:     int(match.Quality),
+		})
+	}
+
+	return result, nil
+}
+
+// Translates the texts with a few requests at a time, splitting the texts longer than
+// the 500 bytes MyMemory accepts.
+func (m *MyMemoryTranslator) TranslateBatch(texts []string) ([]string, error) {
+	translations := make([]string, len(texts))
+	failures := make([]error, len(texts))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, myMemoryConcurrency)
+	for i, text := range texts {
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			translations[i], failures[i] = m.translateLong(text)
+		}(i, text)
 	}
+	wg.Wait()
+
+	for _, err := range failures {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return translations, nil
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&response)
+func (m *MyMemoryTranslator) TranslateFile(path string) (string, error) {
+	text, err := os.ReadFile(path)
 	if err != nil {
-		log.Panic(err)
+		return "", err
 	}
 
-	return response.ResponseData.TranslatedText, nil
+	return m.translateLong(string(text))
+}
+
+// Translates a text of any length, one chunk of at most 500 bytes at a time.
+func (m *MyMemoryTranslator) translateLong(text string) (string, error) {
+	var builder strings.Builder
+	for _, chunk := range splitText(text, myMemoryMaxQuery, func(s string) int { return len(s) }) {
+		if strings.TrimSpace(chunk) == "" {
+			builder.WriteString(chunk)
+			continue
+		}
+		translated, err := m.Translate(chunk)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(keepSurroundingSpace(chunk, translated))
+	}
+
+	return builder.String(), nil
+}
+
+// Returns the query parameters shared by every request.
+func (m *MyMemoryTranslator) params() url.Values {
+	source := m.source
+	if source == "auto" {
+		source = "autodetect"
+	}
+
+	params := url.Values{}
+	params.Set("langpair", source+"|"+m.target)
+	if m.email != "" {
+		params.Set("de", m.email)
+	}
+	if m.key != "" {
+		params.Set("key", m.key)
+	}
+	if m.disableMT {
+		params.Set("mt", "0")
+	}
+	return params
+}
+
+// Sends a GET request to the API and decodes the JSON response into out.
+func (m *MyMemoryTranslator) doJSON(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", m.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(resp, "")
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Returns the subject of a match, sent either as a string or as false when missing.
+func myMemoryString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return ""
+	}
+	return s
+}
+
+func (m *MyMemoryTranslator) GetSupportedLanguages() interface{} {
+	return m.supportedLanguages
+}
+
+// Checks if a language is supported, either by name or by code.
+func (m *MyMemoryTranslator) IsLanguageSupported(language string) bool {
+	if language == "auto" || m.supportedLanguages[strings.ToLower(language)] != "" {
+		return true
+	}
+	for _, code := range m.supportedLanguages {
+		if strings.EqualFold(code, language) || strings.EqualFold(strings.Split(code, "-")[0], language) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,110 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+const myMemoryResponse = `{
+	"responseData": {"translatedText": "Bonjour", "match": 0.99},
+	"quotaFinished": false,
+	"responseDetails": "",
+	"responseStatus": 200,
+	"matches": [
+		{"id": "446398413", "segment": "Hello", "translation": "Bonjour", "source": "en-GB", "target": "fr-FR",
+		 "quality": "74", "reference": null, "usage-count": 2, "subject": "All", "created-by": "MateCat", "match": 1},
+		{"id": 0, "segment": "Hello", "translation": "Salut", "source": "en", "target": "fr",
+		 "quality": 70, "reference": "Machine Translation.", "usage-count": 1, "subject": false, "created-by": "MT!", "match": 0.85}
+	]
+}`
+
+func TestMyMemoryTranslateDetailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		expected := map[string]string{"langpair": "en|fr", "q": "Hello", "de": "me@example.com", "key": "private", "mt": "0"}
+		for key, value := range expected {
+			if q.Get(key) != value {
+				t.Errorf("expected %s=%s, got %q", key, value, q.Get(key))
+			}
+		}
+		w.Write([]byte(myMemoryResponse))
+	}))
+	defer server.Close()
+
+	m := NewMyMemoryTranslator("en", "fr", nil)
+	m.SetBaseURL(server.URL)
+	m.SetEmail("me@example.com")
+	m.SetKey("private")
+	m.SetMachineTranslation(false)
+
+	result, err := m.TranslateDetailed(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Text != "Bonjour" || result.Match != 0.99 || len(result.Matches) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	human, machine := result.Matches[0], result.Matches[1]
+	if human.Machine() || human.Quality != 74 || human.ID != "446398413" || human.Subject != "All" {
+		t.Fatalf("unexpected human match %+v", human)
+	}
+	if !machine.Machine() || machine.Quality != 70 || machine.Match != 0.85 || machine.Subject != "" {
+		t.Fatalf("unexpected machine match %+v", machine)
+	}
+}
+
+func TestMyMemoryQuotaFinished(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responseData":{"translatedText":"MYMEMORY WARNING: YOU USED ALL AVAILABLE FREE TRANSLATIONS FOR TODAY"},` +
+			`"quotaFinished":true,"responseStatus":"429","responseDetails":"MYMEMORY WARNING"}`))
+	}))
+	defer server.Close()
+
+	m := NewMyMemoryTranslator("en", "fr", nil)
+	m.SetBaseURL(server.URL)
+
+	if _, err := m.Translate("Hello"); !errors.Is(err, errs.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+}
+
+func TestMyMemoryTranslateBatchSplitsLongTexts(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		q := r.URL.Query().Get("q")
+		if len(q) > myMemoryMaxQuery {
+			t.Errorf("expected at most %d bytes, got %d", myMemoryMaxQuery, len(q))
+		}
+		w.Write([]byte(`{"responseData":{"translatedText":"` + strings.ToUpper(strings.TrimSpace(q)) + `"},"responseStatus":200}`))
+	}))
+	defer server.Close()
+
+	m := NewMyMemoryTranslator("en", "fr", nil)
+	m.SetBaseURL(server.URL)
+
+	long := strings.Repeat("word ", 150)
+	translations, err := m.TranslateBatch([]string{"hello", long})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if translations[0] != "HELLO" {
+		t.Fatalf("expected 'HELLO', got %s", translations[0])
+	}
+	if translations[1] != strings.ToUpper(long) {
+		t.Fatalf("expected the long text to be translated whole, got %q", translations[1])
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,261 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kashari/go-translate/constants"
+	errs "github.com/kashari/go-translate/errors"
+)
+
+const (
+	// Longest text accepted by the n2mt API, in characters.
+	papagoMaxChars = 5000
+	// Requests sent at the same time by TranslateBatch.
+	papagoConcurrency = 4
+)
+
+type PapagoTranslator struct {
+	apiURL             string
+	source             string
+	target             string
+	proxies            *url.URL
+	client             *http.Client
+	clientID           string
+	clientSecret       string
+	honorific          bool
+	supportedLanguages map[string]string
+}
+
+// A translated text with the source language it was translated from, detected when the
+// source of the translator is auto.
+type PapagoTranslation struct {
+	Text   string
+	Source string
+	Target string
+}
+
+// Error returned by the Papago API, Code being Papago's error code such as N2MT04.
+// It unwraps to the matching errs value so callers can check it with errors.Is.
+type PapagoError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *PapagoError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("papago error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("papago error %s: %s", e.Code, e.Message)
+}
+
+func (e *PapagoError) Unwrap() error {
+	switch e.Code {
+	case "N2MT02", "N2MT04", "N2MT06":
+		return errs.ErrInvalidSourceOrTargetLanguage
+	case "N2MT05":
+		return errs.ErrSameSourceTarget
+	case "N2MT08":
+		return errs.ErrTooLongText
+	case "010":
+		return errs.ErrQuotaExceeded
+	case "024":
+		return errs.ErrUnauthorized
+	}
+
+	return (&StatusError{StatusCode: e.StatusCode}).Unwrap()
+}
+
+// Creates a new instance of PapagoTranslator using the application credentials of the
+// Naver developers console. The languages are Papago codes such as ko, en or zh-CN,
+// the source can be auto to detect it.
+func NewPapagoTranslator(source, target string, proxies *url.URL, clientID, clientSecret string) *PapagoTranslator {
+	return &PapagoTranslator{
+		apiURL:             strings.TrimSuffix(constants.BASE_URLS["PAPAGO_API"], "n2mt"),
+		source:             source,
+		target:             target,
+		proxies:            proxies,
+		client:             newHTTPClient(proxies),
+		clientID:           clientID,
+		clientSecret:       clientSecret,
+		supportedLanguages: constants.PAPAGO_LANGUAGE_TO_CODE,
+	}
+}
+
+// Sets the root URL of the API, mostly useful to point the translator to a stand-in server.
+func (p *PapagoTranslator) SetBaseURL(baseURL string) {
+	p.apiURL = strings.TrimRight(baseURL, "/") + "/"
+}
+
+// Translates into the honorific register, only applied when the target is Korean.
+func (p *PapagoTranslator) SetHonorific(honorific bool) {
+	p.honorific = honorific
+}
+
+func (p *PapagoTranslator) Translate(text string) (string, error) {
+	translations, err := p.TranslateDetailed(context.Background(), []string{text})
+	if err != nil {
+		return "", err
+	}
+
+	return translations[0].Text, nil
+}
+
+func (p *PapagoTranslator) TranslateBatch(texts []string) ([]string, error) {
+	translations, err := p.TranslateDetailed(context.Background(), texts)
+	if err != nil {
+		return nil, err
+	}
+
+	translatedTexts := make([]string, len(translations))
+	for i, translation := range translations {
+		translatedTexts[i] = translation.Text
+	}
+	return translatedTexts, nil
+}
+
+func (p *PapagoTranslator) TranslateFile(path string) (string, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return p.Translate(string(text))
+}
+
+// Translates the texts with a few requests at a time, the API taking a single text per
+// request, and returns the translations in the same order.
+func (p *PapagoTranslator) TranslateDetailed(ctx context.Context, texts []string) ([]PapagoTranslation, error) {
+	for i, text := range texts {
+		if utf8.RuneCountInString(text) > papagoMaxChars {
+			return nil, fmt.Errorf("text %d is longer than %d characters: %w", i, papagoMaxChars, errs.ErrTooLongText)
+		}
+	}
+
+	translations := make([]PapagoTranslation, len(texts))
+	failures := make([]error, len(texts))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, papagoConcurrency)
+	for i, text := range texts {
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			translations[i], failures[i] = p.translate(ctx, text)
+		}(i, text)
+	}
+	wg.Wait()
+
+	for _, err := range failures {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return translations, nil
+}
+
+func (p *PapagoTranslator) translate(ctx context.Context, text string) (PapagoTranslation, error) {
+	if strings.TrimSpace(text) == "" {
+		return PapagoTranslation{Text: text, Source: p.source, Target: p.target}, nil
+	}
+
+	source := p.source
+	if source == "" || source == "auto" {
+		detected, err := p.Detect(ctx, text)
+		if err != nil {
+			return PapagoTranslation{}, err
+		}
+		source = detected
+	}
+	// Papago refuses to translate into the same language
+	if source == p.target {
+		return PapagoTranslation{Text: text, Source: source, Target: p.target}, nil
+	}
+
+	form := url.Values{}
+	form.Set("source", source)
+	form.Set("target", p.target)
+	form.Set("text", text)
+	if p.honorific && p.target == "ko" {
+		form.Set("honorific", "true")
+	}
+
+	var response struct {
+		Message struct {
+			Result struct {
+				SourceLanguage string `json:"srcLangType"`
+				TargetLanguage string `json:"tarLangType"`
+				TranslatedText string `json:"translatedText"`
+			} `json:"result"`
+		} `json:"message"`
+	}
+	if err := p.do(ctx, "n2mt", form, &response); err != nil {
+		return PapagoTranslation{}, err
+	}
+
+	result := response.Message.Result
+	return PapagoTranslation{Text: result.TranslatedText, Source: result.SourceLanguage, Target: result.TargetLanguage}, nil
+}
+
+// Detects the language of the text, returning its Papago code or "unk" when unknown.
+func (p *PapagoTranslator) Detect(ctx context.Context, text string) (string, error) {
+	form := url.Values{}
+	form.Set("query", text)
+
+	var response struct {
+		LangCode string `json:"langCode"`
+	}
+	if err := p.do(ctx, "detectLangs", form, &response); err != nil {
+		return "", err
+	}
+
+	return response.LangCode, nil
+}
+
+func (p *PapagoTranslator) GetSupportedLanguages() interface{} {
+	return p.supportedLanguages
+}
+
+// Checks if a language code is supported.
+func (p *PapagoTranslator) IsLanguageSupported(language string) bool {
+	return language == "auto" || p.supportedLanguages[language] != ""
+}
+
+// Sends an authenticated form request to the API and decodes the JSON response into out.
+// Error responses are returned as *PapagoError.
+func (p *PapagoTranslator) do(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", p.apiURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	req.Header.Set("X-Naver-Client-Id", p.clientID)
+	req.Header.Set("X-Naver-Client-Secret", p.clientSecret)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			ErrorMessage string `json:"errorMessage"`
+			ErrorCode    string `json:"errorCode"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		return &PapagoError{StatusCode: resp.StatusCode, Code: response.ErrorCode, Message: response.ErrorMessage}
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,111 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	errs "github.com/kashari/go-translate/errors"
+)
+
+// Serves n2mt and detectLangs, translating by upper-casing the text.
+func newFakePapago(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Naver-Client-Id") != "id" || r.Header.Get("X-Naver-Client-Secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errorMessage":"Authentication failed","errorCode":"024"}`))
+			return
+		}
+		r.ParseForm()
+
+		switch r.URL.Path {
+		case "/detectLangs":
+			w.Write([]byte(`{"langCode":"ko"}`))
+		case "/n2mt":
+			if r.PostForm.Get("source") == "de" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errorMessage":"Unsupported source language","errorCode":"N2MT02"}`))
+				return
+			}
+			text := strings.ToUpper(r.PostForm.Get("text"))
+			if r.PostForm.Get("honorific") == "true" {
+				text += "요"
+			}
+			w.Write([]byte(`{"message":{"@type":"response","result":{"srcLangType":"` + r.PostForm.Get("source") +
+				`","tarLangType":"` + r.PostForm.Get("target") + `","translatedText":"` + text + `"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPapagoTranslateBatch(t *testing.T) {
+	p := NewPapagoTranslator("en", "ja", nil, "id", "secret")
+	p.SetBaseURL(newFakePapago(t).URL)
+
+	texts := []string{"one", "two", "three", "four", "five", "six"}
+	translations, err := p.TranslateBatch(texts)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for i, text := range texts {
+		if translations[i] != strings.ToUpper(text) {
+			t.Fatalf("expected the translations in order, got %v", translations)
+		}
+	}
+}
+
+func TestPapagoDetectAndHonorific(t *testing.T) {
+	p := NewPapagoTranslator("auto", "en", nil, "id", "secret")
+	p.SetBaseURL(newFakePapago(t).URL)
+	p.SetHonorific(true)
+
+	translations, err := p.TranslateDetailed(context.Background(), []string{"annyeong"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// the honorific register only applies to Korean
+	if translations[0] != (PapagoTranslation{Text: "ANNYEONG", Source: "ko", Target: "en"}) {
+		t.Fatalf("unexpected translation %+v", translations[0])
+	}
+
+	p = NewPapagoTranslator("en", "ko", nil, "id", "secret")
+	p.SetBaseURL(newFakePapago(t).URL)
+	p.SetHonorific(true)
+
+	translation, err := p.Translate("hello")
+	if err != nil || translation != "HELLO요" {
+		t.Fatalf("expected the honorific translation, got %q %v", translation, err)
+	}
+}
+
+func TestPapagoErrors(t *testing.T) {
+	server := newFakePapago(t)
+
+	p := NewPapagoTranslator("en", "ko", nil, "id", "wrong")
+	p.SetBaseURL(server.URL)
+	if _, err := p.Translate("hello"); !errors.Is(err, errs.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	p = NewPapagoTranslator("de", "ko", nil, "id", "secret")
+	p.SetBaseURL(server.URL)
+	_, err := p.Translate("hallo")
+	var papagoErr *PapagoError
+	if !errors.As(err, &papagoErr) || papagoErr.Code != "N2MT02" || !errors.Is(err, errs.ErrInvalidSourceOrTargetLanguage) {
+		t.Fatalf("expected an unsupported language error, got %v", err)
+	}
+
+	if _, err := p.Translate(strings.Repeat("a", papagoMaxChars+1)); !errors.Is(err, errs.ErrTooLongText) {
+		t.Fatalf("expected ErrTooLongText, got %v", err)
+	}
+
+	if err := (&PapagoError{StatusCode: http.StatusTooManyRequests}); !errors.Is(err, errs.ErrTooManyRequests) {
+		t.Fatalf("expected ErrTooManyRequests, got %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/kashari/go-translate/bread"
+	"github.com/kashari/go-translate/constants"
+	errs "github.com/kashari/go-translate/errors"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Looks words up in the PONS dictionary, scraping the website or, with an API key,
+// through the PONS dictionary API.
+type PonsTranslator struct {
+	baseURL            string
+	apiURL             string
+	source             string
+	target             string
+	proxies            *url.URL
+	client             *http.Client
+	apiKey             string
+	supportedLanguages map[string]string
+}
+
+// A headword of a PONS result with its senses, taken either from the website or the API.
+type ponsRom struct {
+	headword  bread.Root
+	wordclass string
+	arabs     []ponsArab
+}
+
+// A sense of a headword, with its translations and examples as source and target pairs.
+type ponsArab struct {
+	header bread.Root
+	pairs  [][2]bread.Root
+}
+
+// Creates a new instance of PonsTranslator, the languages being PONS codes such as en and de.
+func NewPonsTranslator(source, target string, proxies *url.URL) *PonsTranslator {
+	return &PonsTranslator{
+		baseURL:            constants.BASE_URLS["PONS"],
+		apiURL:             constants.BASE_URLS["PONS_API"],
+		source:             source,
+		target:             target,
+		proxies:            proxies,
+		client:             newHTTPClient(proxies),
+		supportedLanguages: constants.PONS_CODES_TO_LANGUAGES,
+	}
+}
+
+// Uses the PONS dictionary API with the given secret instead of scraping the website.
+func (p *PonsTranslator) SetAPIKey(apiKey string) {
+	p.apiKey = apiKey
+}
+
+// Returns the first translation of every sense of the word.
+func (p *PonsTranslator) Translate(word string) ([]string, error) {
+	entries, err := p.Lookup(context.Background(), word)
+	if err != nil {
+		return nil, err
+	}
+
+	var translations []string
+	for _, entry := range entries {
+		sense := ""
+		for i, translation := range entry.Translations {
+			if i == 0 || translation.Sense != sense {
+				translations = append(translations, translation.Text)
+			}
+			sense = translation.Sense
+		}
+	}
+
+	if len(translations) == 0 {
+		return nil, errs.ErrTranslationNotFound
+	}
+	return translations, nil
+}
+
+// Looks the word up and returns an entry for every headword, its translations grouped by sense.
+func (p *PonsTranslator) Lookup(ctx context.Context, word string) ([]DictionaryEntry, error) {
+	if !isInputValid(word, 100) {
+		return nil, fmt.Errorf("invalid input word")
+	}
+	if !p.IsLanguageSupported(p.source) || !p.IsLanguageSupported(p.target) {
+		return nil, errs.ErrLanguageNotSupported
+	}
+
+	var roms []ponsRom
+	var err error
+	if p.apiKey != "" {
+		roms, err = p.fetchAPI(ctx, word)
+	} else {
+		roms, err = p.fetchPage(ctx, word)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]DictionaryEntry, 0, len(roms))
+	for _, rom := range roms {
+		entries = append(entries, ponsEntry(rom))
+	}
+	return entries, nil
+}
+
+func (p *PonsTranslator) GetSupportedLanguages() interface{} {
+	return p.supportedLanguages
+}
+
+// Checks if a language code is supported.
+func (p *PonsTranslator) IsLanguageSupported(language string) bool {
+	return p.supportedLanguages[language] != ""
+}
+
+// Scrapes the dictionary page of the word, such as /translate/english-german/house.
+func (p *PonsTranslator) fetchPage(ctx context.Context, word string) ([]ponsRom, error) {
+	pageURL := fmt.Sprintf("%s%s-%s/%s", p.baseURL, p.supportedLanguages[p.source], p.supportedLanguages[p.target], url.PathEscape(word))
+
+	resp, err := p.get(ctx, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := charset.NewReader(resp.Body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	page, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	root := bread.HTMLParse(string(page))
+	if root.Error != nil {
+		return nil, root.Error
+	}
+
+	// the results of the source language, PONS also lists the ones found the other way round
+	section, ok := findElement(root, "div", "id", p.source)
+	if !ok {
+		section = root
+	}
+
+	var roms []ponsRom
+	for _, romNode := range section.FindAll("div", "class", "rom") {
+		headword, ok := findElement(romNode, "h2")
+		if !ok {
+			continue
+		}
+		rom := ponsRom{headword: headword}
+		for _, translations := range romNode.FindAll("div", "class", "translations") {
+			arab := ponsArab{}
+			arab.header, _ = findElement(translations, "h3")
+			for _, dl := range translations.FindAll("dl") {
+				source, okSource := findElement(dl, "div", "class", "source")
+				target, okTarget := findElement(dl, "div", "class", "target")
+				if okSource && okTarget {
+					arab.pairs = append(arab.pairs, [2]bread.Root{source, target})
+				}
+			}
+			rom.arabs = append(rom.arabs, arab)
+		}
+		roms = append(roms, rom)
+	}
+
+	return roms, nil
+}
+
+// Looks the word up with the dictionary API, whose dictionaries are named after the sorted
+// codes of their languages, such as deen for German and English.
+func (p *PonsTranslator) fetchAPI(ctx context.Context, word string) ([]ponsRom, error) {
+	languages := []string{p.source, p.target}
+	sort.Strings(languages)
+
+	params := url.Values{}
+	params.Set("q", word)
+	params.Set("l", languages[0]+languages[1])
+	params.Set("in", p.source)
+
+	resp, err := p.get(ctx, p.apiURL+"dictionary?"+params.Encode(), map[string]string{"X-Secret": p.apiKey})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// nothing was found
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	var response []struct {
+		Lang string `json:"lang"`
+		Hits []struct {
+			Roms []struct {
+				HeadwordFull string `json:"headword_full"`
+				Wordclass    string `json:"wordclass"`
+				Arabs        []struct {
+					Header       string `json:"header"`
+					Translations []struct {
+						Source string `json:"source"`
+						Target string `json:"target"`
+					} `json:"translations"`
+				} `json:"arabs"`
+			} `json:"roms"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	var roms []ponsRom
+	for _, language := range response {
+		if language.Lang != p.source {
+			continue
+		}
+		for _, hit := range language.Hits {
+			for _, r := range hit.Roms {
+				rom := ponsRom{headword: ponsFragment(r.HeadwordFull), wordclass: r.Wordclass}
+				for _, a := range r.Arabs {
+					arab := ponsArab{header: ponsFragment(a.Header)}
+					for _, translation := range a.Translations {
+						arab.pairs = append(arab.pairs, [2]bread.Root{ponsFragment(translation.Source), ponsFragment(translation.Target)})
+					}
+					rom.arabs = append(rom.arabs, arab)
+				}
+				roms = append(roms, rom)
+			}
+		}
+	}
+
+	return roms, nil
+}
+
+func (p *PonsTranslator) get(ctx context.Context, rawURL string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errs.ErrTranslationNotFound
+		}
+		return nil, newStatusError(resp, "")
+	}
+	return resp, nil
+}
+
+// Builds the dictionary entry of a headword. The first pair of a sense is its translation, as are
+// the following pairs whose source is nothing but the headword, the other ones are examples of
+// the translation before them.
+func ponsEntry(rom ponsRom) DictionaryEntry {
+	entry := DictionaryEntry{Lemma: ponsText(rom.headword, "phonetics", "wordclass", "genus")}
+	if wordclass, ok := findElement(rom.headword, "span", "class", "wordclass"); ok {
+		entry.PartOfSpeech = grammarDescription(ponsAcronym(wordclass))
+	}
+	if rom.wordclass != "" {
+		entry.PartOfSpeech = rom.wordclass
+	}
+	if genus, ok := findElement(rom.headword, "span", "class", "genus"); ok {
+		entry.Gender = grammarDescription(ponsAcronym(genus))
+	}
+
+	for _, arab := range rom.arabs {
+		sense := ponsSense(arab.header)
+		first := len(entry.Translations)
+		for _, pair := range arab.pairs {
+			source := ponsText(pair[0])
+			target := ponsText(pair[1], "genus")
+			if source == "" || target == "" {
+				continue
+			}
+
+			if len(entry.Translations) == first || source == elementText(pair[0], "strong", "class", "headword") {
+				translation := DictionaryTranslation{Text: target, Sense: sense}
+				if genus, ok := findElement(pair[1], "span", "class", "genus"); ok {
+					translation.Gender = grammarDescription(ponsAcronym(genus))
+				}
+				entry.Translations = append(entry.Translations, translation)
+				continue
+			}
+
+			last := &entry.Translations[len(entry.Translations)-1]
+			last.Examples = append(last.Examples, ExamplePair{Source: source, Target: target})
+		}
+	}
+
+	return entry
+}
+
+// Returns the sense of a header such as "1. house (building):", here building.
+func ponsSense(header bread.Root) string {
+	if sense := elementText(header, "span", "class", "sense"); sense != "" {
+		return strings.Trim(sense, "()")
+	}
+	return ""
+}
+
+// Returns the acronym of a grammatical tag, whose title holds the full description.
+func ponsAcronym(r bread.Root) bread.Root {
+	if acronym, ok := findElement(r, "acronym"); ok {
+		return acronym
+	}
+	return r
+}
+
+// Parses an HTML fragment of an API response.
+func ponsFragment(fragment string) bread.Root {
+	root := bread.HTMLParse("<div>" + fragment + "</div>")
+	if body, ok := findElement(root, "div"); ok {
+		return body
+	}
+	return root
+}
+
+// Returns the whitespace normalized text of the element, leaving out the elements of the given classes.
+func ponsText(r bread.Root, skipClasses ...string) string {
+	if r.Pointer == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				builder.WriteString(c.Data)
+			case html.ElementNode:
+				if !hasClass(bread.Root{Pointer: c}, skipClasses...) {
+					walk(c)
+				}
+			}
+		}
+	}
+	walk(r.Pointer)
+
+	return normalizeSpace(builder.String())
+}
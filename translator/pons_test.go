@@ -0,0 +1,118 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Both dictionaries are interchangeable.
+var _ Dictionary = (*PonsTranslator)(nil)
+
+func TestPonsLookupPage(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("testdata", "pons-house.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/english-german/house" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	}))
+	defer server.Close()
+
+	p := NewPonsTranslator("en", "de", nil)
+	p.baseURL = server.URL + "/"
+
+	entries, err := p.Lookup(context.Background(), "house")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+
+	noun := entries[0]
+	if noun.Lemma != "house" || noun.PartOfSpeech != "noun" || len(noun.Translations) != 2 {
+		t.Fatalf("unexpected noun entry %+v", noun)
+	}
+
+	haus := noun.Translations[0]
+	if haus.Text != "Haus" || haus.Gender != "neuter" || haus.Sense != "building" {
+		t.Fatalf("unexpected translation %+v", haus)
+	}
+	if len(haus.Examples) != 3 || haus.Examples[1] != (ExamplePair{Source: "to move house", Target: "umziehen"}) {
+		t.Fatalf("unexpected examples %+v", haus.Examples)
+	}
+	// an example starting with the headword is not a translation
+	if haus.Examples[2] != (ExamplePair{Source: "house prices", Target: "Immobilienpreise"}) {
+		t.Fatalf("unexpected example %+v", haus.Examples[2])
+	}
+	if haushalt := noun.Translations[1]; haushalt.Text != "Haushalt" || haushalt.Gender != "masculine" || haushalt.Sense != "household" {
+		t.Fatalf("unexpected translation %+v", haushalt)
+	}
+
+	verb := entries[1]
+	if verb.PartOfSpeech != "transitive verb" || verb.Translations[0].Text != "jdn unterbringen" || verb.Translations[0].Sense != "" {
+		t.Fatalf("unexpected verb entry %+v", verb)
+	}
+
+	translations, err := p.Translate("house")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(translations) != 3 || translations[1] != "Haushalt" {
+		t.Fatalf("expected the first translation of every sense, got %v", translations)
+	}
+}
+
+func TestPonsLookupAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Header.Get("X-Secret") != "secret" || q.Get("l") != "deen" || q.Get("in") != "en" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if q.Get("q") != "house" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`[{"lang":"en","hits":[{"type":"entry","roms":[{"headword":"house",` +
+			`"headword_full":"house <span class=\"phonetics\">[haʊs]</span> <span class=\"wordclass\">N</span>","wordclass":"noun",` +
+			`"arabs":[{"header":"1. house <span class=\"sense\">(building)</span>:","translations":[` +
+			`{"source":"<strong class=\"headword\">house</strong>","target":"Haus <span class=\"genus\"><acronym title=\"neuter\">nt</acronym></span>"},` +
+			`{"source":"a <strong class=\"headword\">house</strong> of cards","target":"ein Kartenhaus"}]}]}]}]},` +
+			`{"lang":"de","hits":[{"type":"entry","roms":[{"headword":"Haus","headword_full":"Haus","wordclass":"noun","arabs":[]}]}]}]`))
+	}))
+	defer server.Close()
+
+	p := NewPonsTranslator("en", "de", nil)
+	p.apiURL = server.URL + "/"
+	p.SetAPIKey("secret")
+
+	entries, err := p.Lookup(context.Background(), "house")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// the German results are left out
+	if len(entries) != 1 || entries[0].Lemma != "house" || entries[0].PartOfSpeech != "noun" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	translation := entries[0].Translations[0]
+	if translation.Text != "Haus" || translation.Gender != "neuter" || translation.Sense != "building" || len(translation.Examples) != 1 {
+		t.Fatalf("unexpected translation %+v", translation)
+	}
+
+	entries, err = p.Lookup(context.Background(), "xyzzy")
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected no entries, got %+v %v", entries, err)
+	}
+}